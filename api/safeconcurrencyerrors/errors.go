@@ -2,9 +2,88 @@ package safeconcurrencyerrors
 
 // Stop is a special error that can be returned from the
 // [github.com/Izzette/go-safeconcurrency/workpool.ResultCallback] to stop processing results.
+// It may also be returned from the callbacks of the operators of
+// [github.com/Izzette/go-safeconcurrency/generator/ops] to stop consuming the upstream generator.
 //
 //nolint:errname
 const Stop = constantError("stop")
 
 // ErrEventLoopClosed is returned when the event loop is closed and no more snapshots will be available.
 const ErrEventLoopClosed = constantError("event loop closed")
+
+// ErrTenantQueueFull is returned when a task is rejected because the queue of its tenant is full.
+// See [github.com/Izzette/go-safeconcurrency/workpool.TenantConfig].
+const ErrTenantQueueFull = constantError("tenant queue full")
+
+// ErrPoolResumed is returned by [github.com/Izzette/go-safeconcurrency/api/types.PausableWorkerPool.Pause] when the
+// pool is resumed before all running tasks have finished.
+const ErrPoolResumed = constantError("worker pool resumed before running tasks finished")
+
+// ErrSchedulerClosed is returned for tasks which were pending when the scheduler was closed, or which were submitted
+// after it was closed.
+// See [github.com/Izzette/go-safeconcurrency/workpool/scheduler.RejectPending].
+const ErrSchedulerClosed = constantError("scheduler closed")
+
+// ErrRunSuperseded is the cause of the cancellation of a cron run superseded by a newer run.
+// See [github.com/Izzette/go-safeconcurrency/workpool/cron.OverlapCancelPrevious].
+const ErrRunSuperseded = constantError("run superseded by a newer run")
+
+// ErrHedgeLost is the cause of the cancellation of attempts which lost the race against a faster attempt.
+// See [github.com/Izzette/go-safeconcurrency/workpool.SubmitHedged].
+const ErrHedgeLost = constantError("lost to a faster hedged attempt")
+
+// ErrUnregisteredTask is returned when encoding or decoding a task whose type is not registered.
+// See [github.com/Izzette/go-safeconcurrency/workpool/registry.Register].
+const ErrUnregisteredTask = constantError("task type not registered")
+
+// ErrQueueClosed is returned when using a durable queue after it was closed.
+// See [github.com/Izzette/go-safeconcurrency/workpool/diskqueue.Queue].
+const ErrQueueClosed = constantError("queue closed")
+
+// ErrQueueBroken is returned when using a durable queue after a failed compaction left its segments inconsistent with
+// its state, the queue must be closed and re-opened.
+// See [github.com/Izzette/go-safeconcurrency/workpool/diskqueue.Queue.Compact].
+const ErrQueueBroken = constantError("queue broken by a failed compaction")
+
+// ErrCorruptSegment is returned when opening a durable queue with a corrupt segment, other than a partially written
+// record at the end of the last segment.
+// See [github.com/Izzette/go-safeconcurrency/workpool/diskqueue.Open].
+const ErrCorruptSegment = constantError("corrupt queue segment")
+
+// ErrLeaseExpired is returned when acknowledging or extending a lease which expired, or which was already released.
+// See [github.com/Izzette/go-safeconcurrency/workpool/leasequeue.Lease].
+const ErrLeaseExpired = constantError("lease expired")
+
+// ErrMaxReceivesExceeded is the error of the dead letters of messages which were received too many times.
+// See [github.com/Izzette/go-safeconcurrency/workpool/leasequeue.Config].
+const ErrMaxReceivesExceeded = constantError("maximum receive count exceeded")
+
+// ErrWorkerCrashed is returned for tasks whose worker process exited or was killed before responding.
+// See [github.com/Izzette/go-safeconcurrency/workpool/procpool.Pool].
+const ErrWorkerCrashed = constantError("worker process crashed")
+
+// ErrPoolClosed is returned when submitting a task to a pool which was closed.
+const ErrPoolClosed = constantError("pool closed")
+
+// ErrLimitsUnsupported is returned by commands with resource limits on platforms which do not support them.
+// See [github.com/Izzette/go-safeconcurrency/workpool/command.Limits].
+const ErrLimitsUnsupported = constantError("resource limits are not supported on this platform")
+
+// ErrLimitsDisabled is returned by commands with resource limits if the program did not enable them.
+// See [github.com/Izzette/go-safeconcurrency/workpool/command.ExecIfLimited].
+const ErrLimitsDisabled = constantError("resource limits are not enabled")
+
+// ErrSlowConsumer is returned by the generators of the subscribers which were disconnected for not receiving values
+// fast enough.
+// See [github.com/Izzette/go-safeconcurrency/generator/ops.SlowConsumerDisconnect].
+const ErrSlowConsumer = constantError("slow consumer disconnected")
+
+// ErrBroadcastClosed is returned by the generators of the subscribers which joined a broadcast after its source was
+// done.
+// See [github.com/Izzette/go-safeconcurrency/generator/ops.Broadcast.Subscribe].
+const ErrBroadcastClosed = constantError("broadcast closed")
+
+// ErrGeneratorDeadlock is returned when waiting for a generator whose producer is blocked emitting results which are
+// not received.
+// See [github.com/Izzette/go-safeconcurrency/generator.WithDeadlockDetection].
+const ErrGeneratorDeadlock = constantError("generator deadlock: producer blocked emitting unreceived results")
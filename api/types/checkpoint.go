@@ -0,0 +1,34 @@
+package types
+
+import (
+	"context"
+)
+
+// CheckpointEmitter is an [Emitter] passed to [CheckpointProducer.Run], which can also emit checkpoints.
+type CheckpointEmitter[T any, C any] interface {
+	Emitter[T]
+
+	// Checkpoint marks the progress of the producer after all the values emitted so far.
+	// Once all these values are acknowledged by the consumer, the checkpoint is saved to the [CheckpointStore].
+	// If an error is returned, it represents context cancelation, or the failure to save the checkpoint.
+	Checkpoint(context.Context, C) error
+}
+
+// CheckpointProducer is a [Producer] which can resume from a checkpoint.
+// It is used by [github.com/Izzette/go-safeconcurrency/generator/checkpoint.New] to resume long-running producers.
+type CheckpointProducer[T any, C any] interface {
+	// Run should start the producer after the checkpoint and block until all work is completed, optionally returning a
+	// error.
+	// The checkpoint is the last one saved to the [CheckpointStore], or the zero value if none was saved.
+	Run(ctx context.Context, checkpoint C, emitter CheckpointEmitter[T, C]) error
+}
+
+// CheckpointStore persists the checkpoints of a [CheckpointProducer].
+// See [github.com/Izzette/go-safeconcurrency/generator/checkpoint.NewFileStore] for an implementation.
+type CheckpointStore[C any] interface {
+	// Load returns the last saved checkpoint, or the zero value if none was saved.
+	Load(context.Context) (C, error)
+
+	// Save persists the checkpoint, replacing the previous one.
+	Save(context.Context, C) error
+}
@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+// Clock abstracts the passage of time, allowing time-based components to be tested deterministically.
+// See [github.com/Izzette/go-safeconcurrency/clock.Real] for the wall clock, and
+// [github.com/Izzette/go-safeconcurrency/clock.NewFake] for a clock which is advanced manually.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a new [Timer] which will send the current time on its channel after at least the duration.
+	NewTimer(time.Duration) Timer
+}
+
+// Timer represents a single event, as with [time.Timer].
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing.
+	// It returns true if the call stops the timer, false if the timer has already expired or been stopped.
+	Stop() bool
+
+	// Reset changes the timer to expire after the duration.
+	// It returns true if the timer had been active, false if the timer had expired or been stopped.
+	Reset(time.Duration) bool
+}
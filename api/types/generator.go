@@ -8,6 +8,10 @@ import "context"
 // the results channel is drained.
 // This can lead to a deadlock if all results are not completely consumed before calling [types.Generator.Wait], and no
 // mechanism to drain the results channel from another goroutine is in place.
+// The [github.com/Izzette/go-safeconcurrency/generator.Collect],
+// [github.com/Izzette/go-safeconcurrency/generator.ForEach], and
+// [github.com/Izzette/go-safeconcurrency/generator.Drain] helpers consume the results correctly, and
+// [github.com/Izzette/go-safeconcurrency/generator.WithDeadlockDetection] helps to debug such deadlocks.
 //
 // To create generators and for more details, see [github.com/Izzette/go-safeconcurrency/generator.NewBuffered].
 type Generator[T any] interface {
@@ -26,6 +30,57 @@ type Generator[T any] interface {
 	Results() <-chan T
 }
 
+// StoppableGenerator is a [Generator] which can be stopped and inspected without owning its [context.Context].
+// The generators created by [github.com/Izzette/go-safeconcurrency/generator.NewBuffered] implement it, other
+// implementations of [Generator] may not: callers should use a type assertion.
+type StoppableGenerator[T any] interface {
+	Generator[T]
+
+	// Stop cancels the context passed to the producer with
+	// [github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors.Stop], without waiting for it to return.
+	// If the generator is not started yet, the producer is canceled as soon as it is started.
+	// The results must still be consumed until the results channel is closed.
+	Stop()
+
+	// Done returns a channel which is closed once the producer has returned, after its error is available from .Err().
+	Done() <-chan struct{}
+
+	// Err returns the error of the producer once it has returned, or nil if it is still running.
+	// Unlike .Wait(), it never blocks.
+	Err() error
+
+	// State returns the current state of the generator.
+	State() GeneratorState
+}
+
+// GeneratorState is the state of a [StoppableGenerator] in its lifecycle.
+type GeneratorState int
+
+const (
+	// GeneratorCreated is the state of a [Generator] which was not started.
+	GeneratorCreated GeneratorState = iota
+
+	// GeneratorRunning is the state of a [Generator] whose producer is running.
+	GeneratorRunning
+
+	// GeneratorDone is the state of a [Generator] whose producer has returned.
+	GeneratorDone
+)
+
+// String returns the name of the state.
+func (s GeneratorState) String() string {
+	switch s {
+	case GeneratorCreated:
+		return "created"
+	case GeneratorRunning:
+		return "running"
+	case GeneratorDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
 // Producer represents a piece of runnable code.
 // It is used by [Generator] to produce results.
 type Producer[T any] interface {
@@ -14,3 +14,39 @@ type Emitter[T any] interface {
 	// Close closes the emitter and releases any resources.
 	Close()
 }
+
+// Result holds either a value produced by a [ResultProducer], or the error producing a single item.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// ResultEmitter is an [Emitter] of [Result] passed to [ResultProducer.Run], which can fail single items.
+type ResultEmitter[T any] interface {
+	Emitter[Result[T]]
+
+	// EmitValue publishes a successful result, see [Emitter.Emit].
+	EmitValue(context.Context, T) error
+
+	// EmitError publishes the error of a single item, without failing the whole producer.
+	// If an error is returned, it represents context cancelation, see [Emitter.Emit].
+	EmitError(context.Context, error) error
+}
+
+// ResultProducer is a [Producer] whose items may fail individually.
+// It is used by [github.com/Izzette/go-safeconcurrency/generator.NewResults] to produce [Result] values.
+type ResultProducer[T any] interface {
+	// Run should start the producer and block until all work is completed, optionally returning a error.
+	// The error returned by Run fails the whole producer, while [ResultEmitter.EmitError] fails a single item.
+	Run(context.Context, ResultEmitter[T]) error
+}
+
+// LossyEmitter is an [Emitter] which never blocks, dropping values instead once the consumer falls behind.
+// See [github.com/Izzette/go-safeconcurrency/results.NewDropNewestEmitter] and the neighbouring constructors for the
+// policies deciding which values are dropped.
+type LossyEmitter[T any] interface {
+	Emitter[T]
+
+	// Dropped returns the number of values dropped so far.
+	Dropped() uint64
+}
@@ -14,6 +14,8 @@ type WorkerPool[ResourceT any] interface {
 
 	// Close stops the pool, and waits for all tasks to complete.
 	// If the pool was never started, it will be closed immediately.
+	// If the pool is paused (see [PausableWorkerPool]), it is resumed: all the queued tasks are executed before Close
+	// returns.
 	// It is safe to call .Close() multiple times.
 	Close()
 
@@ -22,6 +24,30 @@ type WorkerPool[ResourceT any] interface {
 	Requests() chan<- ValuelessTask[ResourceT]
 }
 
+// PausableWorkerPool is a [WorkerPool] which can be paused without closing it.
+// The pools created by [github.com/Izzette/go-safeconcurrency/workpool.New] implement it, other implementations of
+// [WorkerPool] may not: callers should use a type assertion.
+type PausableWorkerPool[ResourceT any] interface {
+	WorkerPool[ResourceT]
+
+	// Pause stops the workers from picking up new tasks, without closing the pool.
+	// Queued tasks stay queued, running tasks finish, and submitters keep blocking or buffering as usual.
+	// Pause waits until all running tasks have finished, returning the [context.Cause] if the [context.Context] is
+	// canceled first, in which case the pool nonetheless remains paused.
+	// Once Pause has returned, no task is taken from the queue until the pool is resumed.
+	// If the pool is resumed before all running tasks have finished,
+	// [github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors.ErrPoolResumed] is returned.
+	// It is safe to call .Pause() multiple times, or before .Start().
+	Pause(context.Context) error
+
+	// Resume allows the workers to pick up tasks again after a call to .Pause().
+	// It is safe to call .Resume() on a pool which is not paused.
+	Resume()
+
+	// Paused returns true if the pool is paused.
+	Paused() bool
+}
+
 // Task is a simple task representing a unit of work that can be execution in a [WorkerPool].
 // Task produces a single value result, and an error.
 // The task may be submitted to a [WorkerPool] using [github.com/Izzette/go-safeconcurrency/workpool.Submit].
@@ -56,6 +82,63 @@ type ValuelessTask[ResourceT any] interface {
 	Execute(ResourceT)
 }
 
+// TenantTask is an optional interface which may be implemented by a [Task], [StreamingTask], or [ValuelessTask] to
+// identify the tenant on behalf of which it is executed.
+// It is used by pools created with [github.com/Izzette/go-safeconcurrency/workpool.WithFairScheduling] to schedule
+// tasks fairly between tenants.
+// The tenant may alternatively be attached to the task [context.Context] using
+// [github.com/Izzette/go-safeconcurrency/workpool/task.WithTenant].
+type TenantTask interface {
+	// Tenant returns the identifier of the tenant owning the task.
+	Tenant() string
+}
+
+// RejectableTask is an optional interface which may be implemented by a [ValuelessTask] that can be completed with an
+// error without ever being executed.
+// All of the wrappers in [github.com/Izzette/go-safeconcurrency/workpool/task] implement this interface, propagating
+// the error to the [TaskResult].
+type RejectableTask interface {
+	// Reject completes the task with the provided error without executing it.
+	// It must not block, and must not be called after or concurrently with [ValuelessTask.Execute].
+	Reject(error)
+}
+
+// DeadLetter describes a task which failed, as routed to a [DeadLetterSink] by pools created with
+// [github.com/Izzette/go-safeconcurrency/workpool.WithDeadLetters].
+type DeadLetter[ResourceT any] struct {
+	// Task executes the failed task again when sent to a [WorkerPool], its results are discarded.
+	// It carries the values of the [context.Context] of the failed task, but not its cancellation.
+	Task ValuelessTask[ResourceT]
+
+	// Err is the error returned by the last attempt of the task.
+	Err error
+
+	// Attempts is the number of times the task was attempted, see
+	// [github.com/Izzette/go-safeconcurrency/workpool/task.Retry].
+	Attempts uint
+
+	// Metadata is the metadata attached to the [context.Context] of the task using
+	// [github.com/Izzette/go-safeconcurrency/workpool/task.WithMetadata].
+	Metadata map[string]string
+}
+
+// DeadLetterSink receives the tasks which failed in a [WorkerPool].
+// See [github.com/Izzette/go-safeconcurrency/workpool/deadletter] for implementations.
+type DeadLetterSink[ResourceT any] interface {
+	// Put records the [DeadLetter].
+	// It is called by the worker which executed the task, and should not block for long.
+	Put(DeadLetter[ResourceT])
+}
+
+// DeadLetterTask is an optional interface which may be implemented by a [ValuelessTask] which can describe its failure.
+// All of the wrappers in [github.com/Izzette/go-safeconcurrency/workpool/task] implement this interface.
+type DeadLetterTask[ResourceT any] interface {
+	// DeadLetter returns the [DeadLetter] describing the failure of the task, once it has been executed.
+	// The boolean is false if the task succeeded, or if its [context.Context] was canceled, in which case the failure
+	// was not lost but rather abandoned by the submitter.
+	DeadLetter() (DeadLetter[ResourceT], bool)
+}
+
 // TaskFunc can be passed to [github.com/Izzette/go-safeconcurrency/workpool.SubmitFunc] to execute the function as a
 // task.
 type TaskFunc[ResourceT any] func(context.Context, ResourceT) error
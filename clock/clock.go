@@ -0,0 +1,45 @@
+package clock
+
+import (
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Real returns a [types.Clock] backed by the wall clock of the [time] package.
+func Real() types.Clock {
+	return realClock{}
+}
+
+// realClock implements [types.Clock] using the [time] package.
+type realClock struct{}
+
+// Now implements [types.Clock.Now].
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer implements [types.Clock.NewTimer].
+func (realClock) NewTimer(d time.Duration) types.Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// realTimer implements [types.Timer] using [time.Timer].
+type realTimer struct {
+	*time.Timer
+}
+
+// C implements [types.Timer.C].
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// OrReal returns the [types.Clock] if it is not nil, otherwise it returns the [Real] clock.
+// It is convenient for optional clocks in configuration structs.
+func OrReal(clock types.Clock) types.Clock {
+	if clock == nil {
+		return Real()
+	}
+
+	return clock
+}
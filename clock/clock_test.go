@@ -0,0 +1,101 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRealClock(t *testing.T) {
+	c := OrReal(nil)
+	before := time.Now()
+	if now := c.Now(); now.Before(before) {
+		t.Errorf("Expected %v to be after %v", now, before)
+	}
+
+	timer := c.NewTimer(time.Millisecond)
+	<-timer.C()
+	if timer.Stop() {
+		t.Error("Expected Stop to return false for an expired timer")
+	}
+}
+
+func TestFakeTimerFires(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	timer := c.NewTimer(time.Second)
+	if c.Timers() != 1 {
+		t.Errorf("Expected 1 active timer, got %d", c.Timers())
+	}
+
+	c.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("Expected timer not to fire yet")
+	default:
+	}
+
+	c.Advance(time.Millisecond)
+	if fired := <-timer.C(); !fired.Equal(start.Add(time.Second)) {
+		t.Errorf("Expected timer to fire at %v, got %v", start.Add(time.Second), fired)
+	}
+	if c.Timers() != 0 {
+		t.Errorf("Expected no active timers, got %d", c.Timers())
+	}
+}
+
+func TestFakeTimerStopReset(t *testing.T) {
+	c := NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	timer := c.NewTimer(time.Second)
+	if !timer.Stop() {
+		t.Error("Expected Stop to return true for an active timer")
+	}
+	c.Advance(time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("Expected stopped timer not to fire")
+	default:
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("Expected Reset to return false for a stopped timer")
+	}
+	if !timer.Reset(2 * time.Second) {
+		t.Error("Expected Reset to return true for an active timer")
+	}
+	c.Advance(time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("Expected reset timer not to fire yet")
+	default:
+	}
+	c.Advance(time.Second)
+	<-timer.C()
+
+	// Non-positive durations fire immediately.
+	timer.Reset(0)
+	<-timer.C()
+}
+
+func TestFakeWaitForTimers(t *testing.T) {
+	c := NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-c.NewTimer(time.Minute).C()
+	}()
+
+	c.WaitForTimers(1)
+	c.Set(c.Now().Add(time.Hour))
+	<-done
+}
+
+func TestFakeSetBackwardsPanics(t *testing.T) {
+	c := NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when moving the clock backwards")
+		}
+	}()
+	c.Set(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+}
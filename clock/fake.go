@@ -0,0 +1,172 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Fake is a [types.Clock] which only advances when [Fake.Advance] or [Fake.Set] are called.
+// It is intended to test time-based components deterministically.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+	// timers contains the active timers.
+	timers []*fakeTimer
+	// changed is closed and replaced each time a timer is armed.
+	changed chan struct{}
+}
+
+// NewFake creates a new [Fake] clock starting at the provided time.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start, changed: make(chan struct{})}
+}
+
+// Now implements [types.Clock.Now].
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer implements [types.Clock.NewTimer].
+func (c *Fake) NewTimer(d time.Duration) types.Timer {
+	timer := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.arm(timer, d)
+
+	return timer
+}
+
+// Advance moves the clock forward by the duration, firing all timers which expire in the meantime.
+func (c *Fake) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(c.now.Add(d))
+}
+
+// Set moves the clock to the provided time, firing all timers which expire in the meantime.
+// The clock may not be moved backwards.
+func (c *Fake) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Before(c.now) {
+		panic("attempt to move clock.Fake backwards")
+	}
+	c.set(now)
+}
+
+// Timers returns the number of active timers.
+func (c *Fake) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// WaitForTimers blocks until at least n timers are active.
+// It is useful to wait for a goroutine under test to arm its timers before advancing the clock.
+func (c *Fake) WaitForTimers(n int) {
+	c.mu.Lock()
+	for len(c.timers) < n {
+		changed := c.changed
+		c.mu.Unlock()
+		<-changed
+		c.mu.Lock()
+	}
+	c.mu.Unlock()
+}
+
+// set fires all the expired timers in order of expiration.
+// The caller must hold the lock.
+func (c *Fake) set(now time.Time) {
+	c.now = now
+
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].when.Before(c.timers[j].when)
+	})
+	fired := 0
+	for _, timer := range c.timers {
+		if timer.when.After(now) {
+			break
+		}
+		timer.fire(now)
+		fired++
+	}
+	c.timers = c.timers[fired:]
+}
+
+// arm activates the timer to expire after the duration.
+// The caller must hold the lock.
+func (c *Fake) arm(timer *fakeTimer, d time.Duration) {
+	timer.when = c.now.Add(d)
+	if d <= 0 {
+		timer.fire(c.now)
+
+		return
+	}
+
+	c.timers = append(c.timers, timer)
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// disarm deactivates the timer, returning true if it was active.
+// The caller must hold the lock.
+func (c *Fake) disarm(timer *fakeTimer) bool {
+	for i, active := range c.timers {
+		if active == timer {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// fakeTimer implements [types.Timer] for the [Fake] clock.
+type fakeTimer struct {
+	clock *Fake
+	when  time.Time
+	c     chan time.Time
+}
+
+// C implements [types.Timer.C].
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop implements [types.Timer.Stop].
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.disarm(t)
+}
+
+// Reset implements [types.Timer.Reset].
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.clock.disarm(t)
+	t.clock.arm(t, d)
+
+	return active
+}
+
+// fire delivers the time to the channel, dropping it if a previous time has not been received, as with [time.Timer].
+func (t *fakeTimer) fire(now time.Time) {
+	select {
+	case t.c <- now:
+	default:
+	}
+}
@@ -10,6 +10,9 @@ import (
 
 // HttpTask implements [types.Task].
 // It fetches a URL and returns the HTTP response.
+// The body of the response is closed before it is returned, as the worker would otherwise be released while the body is
+// still being read.
+// To stream response bodies, use [github.com/Izzette/go-safeconcurrency/workpool/httptransport.Transport] instead.
 type HttpTask struct {
 	// The HTTP Method to use.
 	Method string
@@ -0,0 +1,184 @@
+package checkpoint
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// Item is a value emitted by a [types.CheckpointProducer], which must be acknowledged once it is processed.
+type Item[T any] struct {
+	Value T
+
+	tracker acknowledger
+	seq     uint64
+}
+
+// Ack acknowledges that the value was processed by the consumer.
+// Once all the values emitted before a checkpoint are acknowledged, the checkpoint is saved, and the error saving it
+// is returned.
+// The values may be acknowledged in any order and from any goroutine, acknowledging a value again has no effect.
+func (i Item[T]) Ack(ctx context.Context) error {
+	return i.tracker.ack(ctx, i.seq)
+}
+
+// New creates (but does not start) a [types.Generator] of [Item] with the specified results buffer size.
+// The producer is run from the checkpoint loaded from the store, and the checkpoints it emits are saved to the store
+// once all the values emitted before them are acknowledged with [Item.Ack].
+func New[T any, C any](
+	producer types.CheckpointProducer[T, C],
+	store types.CheckpointStore[C],
+	buffer uint,
+) types.Generator[Item[T]] {
+	return generator.NewBuffered[Item[T]](&checkpointProducer[T, C]{producer: producer, store: store}, buffer)
+}
+
+// checkpointProducer implements [types.Producer] for a [types.CheckpointProducer].
+type checkpointProducer[T any, C any] struct {
+	producer types.CheckpointProducer[T, C]
+	store    types.CheckpointStore[C]
+}
+
+// Run implements [types.Producer.Run].
+func (p *checkpointProducer[T, C]) Run(ctx context.Context, emitter types.Emitter[Item[T]]) error {
+	checkpoint, err := p.store.Load(ctx)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	return p.producer.Run(ctx, checkpoint, &checkpointEmitter[T, C]{
+		Emitter: emitter,
+		tracker: &tracker[C]{store: p.store, acked: make(map[uint64]struct{})},
+	})
+}
+
+// checkpointEmitter implements [types.CheckpointEmitter].
+type checkpointEmitter[T any, C any] struct {
+	types.Emitter[Item[T]]
+	tracker *tracker[C]
+}
+
+// Emit implements [types.Emitter.Emit].
+func (e *checkpointEmitter[T, C]) Emit(ctx context.Context, value T) error {
+	//nolint:wrapcheck
+	return e.Emitter.Emit(ctx, Item[T]{Value: value, tracker: e.tracker, seq: e.tracker.emit()})
+}
+
+// Checkpoint implements [types.CheckpointEmitter.Checkpoint].
+func (e *checkpointEmitter[T, C]) Checkpoint(ctx context.Context, checkpoint C) error {
+	return e.tracker.checkpoint(ctx, checkpoint)
+}
+
+// acknowledger is implemented by [tracker], it allows [Item] not to depend on the type of the checkpoints.
+type acknowledger interface {
+	ack(ctx context.Context, seq uint64) error
+}
+
+// pendingCheckpoint is a checkpoint waiting for the values emitted before it to be acknowledged.
+type pendingCheckpoint[C any] struct {
+	// seq is the number of values emitted before the checkpoint.
+	seq        uint64
+	checkpoint C
+}
+
+// tracker tracks the acknowledged values, and saves the checkpoints once all the values before them are acknowledged.
+type tracker[C any] struct {
+	store types.CheckpointStore[C]
+
+	mu sync.Mutex
+	// next is the sequence number of the next value.
+	next uint64
+	// done is the number of values acknowledged in order, all the values with a lower sequence number are acknowledged.
+	done uint64
+	// acked holds the sequence numbers of the values acknowledged out of order.
+	acked map[uint64]struct{}
+	// pending holds the checkpoints which were not yet reached, in order.
+	pending []pendingCheckpoint[C]
+	// reached is incremented for each checkpoint reached.
+	reached uint64
+
+	// saveMu serializes the saves, so that an older checkpoint does not replace a newer one.
+	saveMu sync.Mutex
+	// saved is the value of reached when the last checkpoint was saved.
+	saved uint64
+}
+
+// emit returns the sequence number of the next value.
+func (t *tracker[C]) emit() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seq := t.next
+	t.next++
+
+	return seq
+}
+
+// checkpoint adds a checkpoint after the values emitted so far, saving it if they are all already acknowledged.
+func (t *tracker[C]) checkpoint(ctx context.Context, checkpoint C) error {
+	t.mu.Lock()
+	t.pending = append(t.pending, pendingCheckpoint[C]{seq: t.next, checkpoint: checkpoint})
+	save, reached, ok := t.reach()
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	return t.save(ctx, save, reached)
+}
+
+// ack acknowledges the value, saving the last checkpoint reached if any.
+func (t *tracker[C]) ack(ctx context.Context, seq uint64) error {
+	t.mu.Lock()
+	if seq >= t.done {
+		t.acked[seq] = struct{}{}
+	}
+	for {
+		if _, ok := t.acked[t.done]; !ok {
+			break
+		}
+		delete(t.acked, t.done)
+		t.done++
+	}
+	save, reached, ok := t.reach()
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	return t.save(ctx, save, reached)
+}
+
+// reach removes the pending checkpoints whose values are all acknowledged, returning the last of them.
+// It must be called with the mutex held.
+func (t *tracker[C]) reach() (C, uint64, bool) {
+	var last C
+	ok := false
+	for len(t.pending) > 0 && t.pending[0].seq <= t.done {
+		last, ok = t.pending[0].checkpoint, true
+		t.pending = t.pending[1:]
+		t.reached++
+	}
+
+	return last, t.reached, ok
+}
+
+// save saves the checkpoint, unless a later checkpoint was already saved.
+func (t *tracker[C]) save(ctx context.Context, checkpoint C, reached uint64) error {
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+
+	if reached <= t.saved {
+		return nil
+	}
+	if err := t.store.Save(ctx, checkpoint); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	t.saved = reached
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// memoryStore is an in-memory [types.CheckpointStore] recording the saved checkpoints.
+type memoryStore struct {
+	mu    sync.Mutex
+	saved []int
+	err   error
+}
+
+func (s *memoryStore) Load(context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.saved) == 0 {
+		return 0, nil
+	}
+
+	return s.saved[len(s.saved)-1], nil
+}
+
+func (s *memoryStore) Save(_ context.Context, checkpoint int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.saved = append(s.saved, checkpoint)
+
+	return nil
+}
+
+// rowsProducer emits the rows from the checkpoint, with a checkpoint after every other row.
+type rowsProducer struct {
+	rows int
+}
+
+func (p *rowsProducer) Run(ctx context.Context, from int, emitter types.CheckpointEmitter[int, int]) error {
+	for row := from; row < p.rows; row++ {
+		if err := emitter.Emit(ctx, row); err != nil {
+			return err
+		}
+		if row%2 == 1 {
+			if err := emitter.Checkpoint(ctx, row+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestCheckpointResume(t *testing.T) {
+	store := &memoryStore{}
+	gen := New[int, int](&rowsProducer{rows: 6}, store, 0)
+	gen.Start(context.Background())
+
+	// The third value is received but never acknowledged, as if the consumer crashed.
+	first, second, third := <-gen.Results(), <-gen.Results(), <-gen.Results()
+	if third.Value != 2 {
+		t.Errorf("Expected 2, got %d", third.Value)
+	}
+
+	// The values are acknowledged out of order, the checkpoint is saved once both values before it are acknowledged.
+	if err := second.Ack(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if checkpoint, _ := store.Load(context.Background()); checkpoint != 0 {
+		t.Errorf("Expected no checkpoint, got %d", checkpoint)
+	}
+	if err := first.Ack(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if checkpoint, _ := store.Load(context.Background()); checkpoint != 2 {
+		t.Errorf("Expected checkpoint 2, got %d", checkpoint)
+	}
+	gen.(types.StoppableGenerator[Item[int]]).Stop()
+	if err := generator.Drain(gen); !errors.Is(err, safeconcurrencyerrors.Stop) {
+		t.Fatalf("Expected Stop, got %v", err)
+	}
+
+	// The producer resumes from the last checkpoint.
+	gen = New[int, int](&rowsProducer{rows: 6}, store, 0)
+	gen.Start(context.Background())
+	values := make([]int, 0)
+	for item := range gen.Results() {
+		values = append(values, item.Value)
+		if err := item.Ack(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := gen.Wait(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []int{2, 3, 4, 5}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	if expected := []int{2, 4, 6}; !reflect.DeepEqual(store.saved, expected) {
+		t.Errorf("Expected checkpoints %v, got %v", expected, store.saved)
+	}
+}
+
+func TestCheckpointSaveError(t *testing.T) {
+	saveErr := errors.New("save error")
+	gen := New[int, int](&rowsProducer{rows: 3}, &memoryStore{err: saveErr}, 0)
+	gen.Start(context.Background())
+
+	// The checkpoint was emitted once the third value is received, it is saved by the last acknowledgement.
+	first, second := <-gen.Results(), <-gen.Results()
+	<-gen.Results()
+	if err := first.Ack(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := second.Ack(context.Background()); !errors.Is(err, saveErr) {
+		t.Errorf("Expected the save error, got %v", err)
+	}
+	if err := generator.Drain(gen); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// NewFileStore creates a [types.CheckpointStore] persisting the checkpoints to the file at the path, encoded as JSON.
+// The file is replaced atomically, by renaming a temporary file of the same directory.
+func NewFileStore[C any](path string) types.CheckpointStore[C] {
+	return &fileStore[C]{path: path}
+}
+
+// fileStore implements [types.CheckpointStore] for [NewFileStore].
+type fileStore[C any] struct {
+	path string
+}
+
+// Load implements [types.CheckpointStore.Load].
+func (s *fileStore[C]) Load(_ context.Context) (C, error) {
+	var checkpoint C
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return checkpoint, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return checkpoint, fmt.Errorf("decoding checkpoint from %s: %w", s.path, err)
+	}
+
+	return checkpoint, nil
+}
+
+// Save implements [types.CheckpointStore.Save].
+func (s *fileStore[C]) Save(_ context.Context, checkpoint C) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("encoding checkpoint: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	// The temporary file is removed if it was not renamed.
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), s.path)
+	}
+	if err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package checkpoint
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	type cursor struct {
+		Table string
+		Row   int
+	}
+	store := NewFileStore[cursor](filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	checkpoint, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if checkpoint != (cursor{}) {
+		t.Errorf("Expected the zero checkpoint, got %v", checkpoint)
+	}
+
+	for _, saved := range []cursor{{Table: "users", Row: 100}, {Table: "orders", Row: 3}} {
+		if err := store.Save(context.Background(), saved); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		checkpoint, err := store.Load(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if checkpoint != saved {
+			t.Errorf("Expected %v, got %v", saved, checkpoint)
+		}
+	}
+}
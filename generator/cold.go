@@ -0,0 +1,24 @@
+package generator
+
+import (
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Cold creates independent generators of the same [types.Producer], each subscriber running the producer anew.
+// The producer must be safe to run concurrently, as the generators of different subscribers may run concurrently.
+// See [github.com/Izzette/go-safeconcurrency/generator/ops.Share] for sharing a single run between subscribers.
+type Cold[T any] struct {
+	producer types.Producer[T]
+	buffer   uint
+}
+
+// NewCold creates a [Cold] generator factory of the producer, with the specified results buffer size.
+func NewCold[T any](producer types.Producer[T], buffer uint) *Cold[T] {
+	return &Cold[T]{producer: producer, buffer: buffer}
+}
+
+// Subscribe creates (but does not start) a new generator running the producer.
+// Unlike the generators themselves, Subscribe may be called any number of times.
+func (c *Cold[T]) Subscribe() types.Generator[T] {
+	return NewBuffered(c.producer, c.buffer)
+}
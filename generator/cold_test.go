@@ -0,0 +1,28 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestColdRunsProducerForEachSubscriber(t *testing.T) {
+	values := []int{1, 2, 3}
+	cold := NewCold[int](&testProducer{values: values}, 0)
+
+	for i := 0; i < 2; i++ {
+		gen := cold.Subscribe()
+		gen.Start(context.Background())
+
+		var received []int
+		for val := range gen.Results() {
+			received = append(received, val)
+		}
+		if !reflect.DeepEqual(received, values) {
+			t.Errorf("expected %v, got %v", values, received)
+		}
+		if err := gen.Wait(); err != nil {
+			t.Errorf("unexpected error from Wait: %v", err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Collect starts the generator, and returns all its results once it completes.
+// The results produced before a failure are returned along with the error.
+func Collect[T any](ctx context.Context, gen types.Generator[T]) ([]T, error) {
+	gen.Start(ctx)
+
+	values := make([]T, 0)
+	for value := range gen.Results() {
+		values = append(values, value)
+	}
+
+	//nolint:wrapcheck
+	return values, gen.Wait()
+}
+
+// ForEach starts the generator, and calls the callback for each of its results.
+// If the callback returns an error, the generator is canceled, its remaining results are discarded, and the error is
+// returned.
+// If the callback returns [safeconcurrencyerrors.Stop], the generator is canceled and no error is returned.
+// Otherwise, the error of the generator is returned once it completes.
+func ForEach[T any](ctx context.Context, gen types.Generator[T], callback func(context.Context, T) error) error {
+	genCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(context.Canceled)
+	gen.Start(genCtx)
+
+	var err error
+	for value := range gen.Results() {
+		if err = callback(ctx, value); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		cancel(safeconcurrencyerrors.Stop)
+	}
+	genErr := Drain(gen)
+	if errors.Is(err, safeconcurrencyerrors.Stop) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return genErr
+}
+
+// Drain discards the remaining results of the started generator, and returns its error once it completes.
+// It is used to wait for a generator whose results are no longer needed, typically after canceling it.
+func Drain[T any](gen types.Generator[T]) error {
+	for range gen.Results() {
+		// Discard the results which are no longer needed.
+	}
+
+	//nolint:wrapcheck
+	return gen.Wait()
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+)
+
+func TestCollect(t *testing.T) {
+	producerErr := errors.New("producer error")
+	values, err := Collect(context.Background(), New[int](&testProducer{values: []int{1, 2}, err: producerErr}))
+	if !errors.Is(err, producerErr) {
+		t.Errorf("expected the producer error, got %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1, 2}) {
+		t.Errorf("expected [1 2], got %v", values)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	var received []int
+	err := ForEach(context.Background(), New[int](&testProducer{values: []int{1, 2, 3}}),
+		func(_ context.Context, value int) error {
+			received = append(received, value)
+			if value == 2 {
+				return safeconcurrencyerrors.Stop
+			}
+
+			return nil
+		})
+	if err != nil {
+		t.Errorf("unexpected error from ForEach: %v", err)
+	}
+	if !reflect.DeepEqual(received, []int{1, 2}) {
+		t.Errorf("expected [1 2], got %v", received)
+	}
+
+	callbackErr := errors.New("callback error")
+	err = ForEach(context.Background(), New[int](&testProducer{values: []int{1, 2, 3}}),
+		func(context.Context, int) error {
+			return callbackErr
+		})
+	if !errors.Is(err, callbackErr) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	producerErr := errors.New("producer error")
+	gen := New[int](&testProducer{values: []int{1, 2, 3}, err: producerErr})
+	gen.Start(context.Background())
+
+	if err := Drain(gen); !errors.Is(err, producerErr) {
+		t.Errorf("expected the producer error, got %v", err)
+	}
+}
+
+func TestDeadlockDetection(t *testing.T) {
+	gen := New[int](&testProducer{values: []int{1, 2}}, WithDeadlockDetection(10*time.Millisecond))
+	gen.Start(context.Background())
+
+	// The results are never received.
+	err := gen.Wait()
+	if !errors.Is(err, safeconcurrencyerrors.ErrGeneratorDeadlock) {
+		t.Fatalf("expected ErrGeneratorDeadlock, got %v", err)
+	}
+	var deadlockErr *DeadlockError
+	if !errors.As(err, &deadlockErr) {
+		t.Fatalf("expected a DeadlockError, got %T", err)
+	}
+	if !bytes.Contains(deadlockErr.ProducerStack, []byte("testProducer")) {
+		t.Errorf("expected the producer stack, got %s", deadlockErr.ProducerStack)
+	}
+	if !bytes.Contains(deadlockErr.WaitStack, []byte("TestDeadlockDetection")) {
+		t.Errorf("expected the wait stack, got %s", deadlockErr.WaitStack)
+	}
+}
+
+func TestDeadlockDetectionWithConsumer(t *testing.T) {
+	gen := New[int](&testProducer{values: []int{1, 2, 3}}, WithDeadlockDetection(time.Minute))
+	values, err := Collect(context.Background(), gen)
+	if err != nil {
+		t.Errorf("unexpected error from Collect: %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v", values)
+	}
+}
@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Option configures the generators created by [New] and [NewBuffered].
+type Option func(*options)
+
+// options is configured by [Option].
+type options struct {
+	deadlockTimeout time.Duration
+}
+
+// WithDeadlockDetection enables a debug mode detecting a producer blocked in [types.Emitter.Emit] while
+// [types.Generator.Wait] is in progress and no one receives the results.
+// Once the producer has been blocked for the timeout during a call to [types.Generator.Wait], the producer is canceled
+// and a [*DeadlockError] is returned instead of blocking forever.
+//
+// The detection cannot distinguish a deadlock from a consumer receiving the results slower than the timeout, the
+// timeout should be much longer than the processing of a single result.
+// Capturing the stack of the producer on each blocking emit is expensive, this mode should not be used in production.
+func WithDeadlockDetection(timeout time.Duration) Option {
+	return func(o *options) {
+		o.deadlockTimeout = timeout
+	}
+}
+
+// DeadlockError is returned by [types.Generator.Wait] once a deadlock is detected, see [WithDeadlockDetection].
+// It wraps [safeconcurrencyerrors.ErrGeneratorDeadlock].
+type DeadlockError struct {
+	// ProducerStack is the stack of the producer goroutine blocked in [types.Emitter.Emit].
+	ProducerStack []byte
+	// WaitStack is the stack of the goroutine blocked in [types.Generator.Wait].
+	WaitStack []byte
+}
+
+// Error implements the error interface for [DeadlockError].
+func (e *DeadlockError) Error() string {
+	return fmt.Sprintf(
+		"%v: producer blocked in Emit:\n%s\nconsumer blocked in Wait:\n%s",
+		safeconcurrencyerrors.ErrGeneratorDeadlock,
+		e.ProducerStack,
+		e.WaitStack,
+	)
+}
+
+// Unwrap implements the error interface for [DeadlockError].
+func (e *DeadlockError) Unwrap() error {
+	return safeconcurrencyerrors.ErrGeneratorDeadlock
+}
+
+// blockedEmit records a call to [types.Emitter.Emit] which could not publish its result immediately.
+type blockedEmit struct {
+	stack []byte
+	since time.Time
+}
+
+// debugEmitter implements [types.Emitter], recording the calls blocked in [types.Emitter.Emit].
+type debugEmitter[T any] struct {
+	types.Emitter[T]
+	results chan<- T
+	// blocked is nil unless a call to Emit is blocked.
+	blocked *atomic.Pointer[blockedEmit]
+}
+
+// Emit implements [types.Emitter.Emit].
+func (e *debugEmitter[T]) Emit(ctx context.Context, value T) error {
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	select {
+	case e.results <- value:
+		return nil
+	default:
+	}
+
+	e.blocked.Store(&blockedEmit{stack: debug.Stack(), since: time.Now()})
+	defer e.blocked.Store(nil)
+
+	//nolint:wrapcheck
+	return e.Emitter.Emit(ctx, value)
+}
+
+// waitDetectingDeadlock waits for the producer to complete, canceling it if it is blocked in
+// [types.Emitter.Emit] for the deadlock timeout.
+func (gen *generator[T]) waitDetectingDeadlock() error {
+	timer := time.NewTimer(gen.options.deadlockTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-gen.done:
+			return gen.err
+		case <-timer.C:
+		}
+
+		blocked := gen.blocked.Load()
+		if blocked != nil && time.Since(blocked.since) >= gen.options.deadlockTimeout {
+			err := &DeadlockError{ProducerStack: blocked.stack, WaitStack: debug.Stack()}
+			gen.stop(err)
+			<-gen.done
+
+			return err
+		}
+		timer.Reset(gen.options.deadlockTimeout)
+	}
+}
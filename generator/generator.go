@@ -2,8 +2,12 @@ package generator
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
 	"sync/atomic"
 
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
 	"github.com/Izzette/go-safeconcurrency/api/types"
 	"github.com/Izzette/go-safeconcurrency/results"
 )
@@ -11,20 +15,28 @@ import (
 // New creates (but does not start) a basic implementation of [types.Generator] with no results buffering.
 // If you would like results buffering, use [NewBuffered] instead.
 // This is equivalent to calling [NewBuffered] with a buffer size of 0.
-func New[T any](producer types.Producer[T]) types.Generator[T] {
-	return NewBuffered(producer, 0)
+func New[T any](producer types.Producer[T], opts ...Option) types.Generator[T] {
+	return NewBuffered(producer, 0, opts...)
 }
 
 // NewBuffered creates (but does not start) a basic implementation of [types.Generator] with the specified
 // results buffer size.
 // It is not re-startable, and thus [types.Generator.Start] or [types.Generator.Run] must only be called exactly once.
-func NewBuffered[T any](producer types.Producer[T], buffer uint) types.Generator[T] {
-	return &generator[T]{
+// The generator implements [types.StoppableGenerator].
+func NewBuffered[T any](producer types.Producer[T], buffer uint, opts ...Option) types.Generator[T] {
+	gen := &generator[T]{
 		producer: producer,
 		results:  make(chan T, buffer),
 		done:     make(chan struct{}),
 		started:  &atomic.Bool{},
+		blocked:  &atomic.Pointer[blockedEmit]{},
+		mu:       &sync.Mutex{},
 	}
+	for _, opt := range opts {
+		opt(&gen.options)
+	}
+
+	return gen
 }
 
 // generator implements [types.Generator].
@@ -34,6 +46,40 @@ type generator[T any] struct {
 	done     chan struct{}
 	err      error
 	started  *atomic.Bool
+	options  options
+	// blocked is only used with [WithDeadlockDetection].
+	blocked *atomic.Pointer[blockedEmit]
+
+	// mu protects the fields below.
+	mu *sync.Mutex
+	// cancel cancels the context of the producer, it is nil until the generator is started.
+	cancel context.CancelCauseFunc
+	// stopCause is the cause of the cancellation requested before the generator was started, if any.
+	stopCause error
+}
+
+// ProducerPanicError is returned by [types.Generator.Wait] when the [types.Producer] panics.
+// The panic is recovered, so that it does not crash the process.
+type ProducerPanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack of the producer goroutine when it panicked.
+	Stack []byte
+}
+
+// Error implements the error interface for [ProducerPanicError].
+func (e *ProducerPanicError) Error() string {
+	return fmt.Sprintf("producer panicked: %v\n%s", e.Value, e.Stack)
+}
+
+// Unwrap implements the error interface for [ProducerPanicError].
+// It returns the value passed to panic if it is an error.
+func (e *ProducerPanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
 }
 
 // Start implements [types.Generator.Start].
@@ -42,7 +88,17 @@ func (gen *generator[T]) Start(ctx context.Context) {
 		panic("attempt to start previously started generator.Generator")
 	}
 
+	gen.mu.Lock()
+	ctx, gen.cancel = context.WithCancelCause(ctx)
+	if gen.stopCause != nil {
+		gen.cancel(gen.stopCause)
+	}
+	gen.mu.Unlock()
+
 	h := results.NewEmitter(gen.results)
+	if gen.options.deadlockTimeout > 0 {
+		h = &debugEmitter[T]{Emitter: h, results: gen.results, blocked: gen.blocked}
+	}
 	go gen.startInner(ctx, h)
 }
 
@@ -55,12 +111,50 @@ func (gen *generator[T]) Run(ctx context.Context) error {
 
 // Wait implements [types.Generator.Wait].
 func (gen *generator[T]) Wait() error {
+	if gen.options.deadlockTimeout > 0 {
+		return gen.waitDetectingDeadlock()
+	}
+
 	// The done channel is always closed when the Producer completes, after setting w.err.
 	<-gen.done
 
 	return gen.err
 }
 
+// Stop implements [types.StoppableGenerator.Stop].
+func (gen *generator[T]) Stop() {
+	gen.stop(safeconcurrencyerrors.Stop)
+}
+
+// Done implements [types.StoppableGenerator.Done].
+func (gen *generator[T]) Done() <-chan struct{} {
+	return gen.done
+}
+
+// Err implements [types.StoppableGenerator.Err].
+func (gen *generator[T]) Err() error {
+	select {
+	case <-gen.done:
+		return gen.err
+	default:
+		return nil
+	}
+}
+
+// State implements [types.StoppableGenerator.State].
+func (gen *generator[T]) State() types.GeneratorState {
+	select {
+	case <-gen.done:
+		return types.GeneratorDone
+	default:
+	}
+	if gen.started.Load() {
+		return types.GeneratorRunning
+	}
+
+	return types.GeneratorCreated
+}
+
 // Results implements [types.Generator.Results].
 func (gen *generator[T]) Results() <-chan T {
 	return gen.results
@@ -70,6 +164,27 @@ func (gen *generator[T]) Results() <-chan T {
 func (gen *generator[T]) startInner(ctx context.Context, h types.Emitter[T]) {
 	defer h.Close()
 	defer close(gen.done)
+	defer gen.cancel(context.Canceled)
+	defer func() {
+		if r := recover(); r != nil {
+			gen.err = &ProducerPanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
 
 	gen.err = gen.producer.Run(ctx, h)
 }
+
+// stop cancels the context of the producer with the cause, or records the cause if the generator is not started.
+func (gen *generator[T]) stop(cause error) {
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	if gen.cancel == nil {
+		if gen.stopCause == nil {
+			gen.stopCause = cause
+		}
+
+		return
+	}
+	gen.cancel(cause)
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
 	"github.com/Izzette/go-safeconcurrency/api/types"
 )
 
@@ -140,3 +141,68 @@ func TestBufferedGenerator(t *testing.T) {
 		t.Errorf("unexpected error from generator: %v", err)
 	}
 }
+
+type blockingProducer struct{}
+
+func (r *blockingProducer) Run(ctx context.Context, _ types.Emitter[int]) error {
+	<-ctx.Done()
+
+	return context.Cause(ctx)
+}
+
+func TestGeneratorStop(t *testing.T) {
+	gen := New[int](&blockingProducer{}).(types.StoppableGenerator[int])
+	if state := gen.State(); state != types.GeneratorCreated {
+		t.Errorf("expected state created, got %v", state)
+	}
+
+	gen.Start(context.Background())
+	if state := gen.State(); state != types.GeneratorRunning {
+		t.Errorf("expected state running, got %v", state)
+	}
+	if err := gen.Err(); err != nil {
+		t.Errorf("expected no error while running, got %v", err)
+	}
+
+	gen.Stop()
+	<-gen.Done()
+	if state := gen.State(); state != types.GeneratorDone {
+		t.Errorf("expected state done, got %v", state)
+	}
+	if err := gen.Err(); !errors.Is(err, safeconcurrencyerrors.Stop) {
+		t.Errorf("expected Stop, got %v", err)
+	}
+}
+
+func TestGeneratorStopBeforeStart(t *testing.T) {
+	gen := New[int](&blockingProducer{}).(types.StoppableGenerator[int])
+	gen.Stop()
+
+	if err := gen.Run(context.Background()); !errors.Is(err, safeconcurrencyerrors.Stop) {
+		t.Errorf("expected Stop, got %v", err)
+	}
+}
+
+type panickingProducer struct {
+	value any
+}
+
+func (r *panickingProducer) Run(context.Context, types.Emitter[int]) error {
+	panic(r.value)
+}
+
+func TestGeneratorRecoversProducerPanic(t *testing.T) {
+	panicErr := errors.New("panic error")
+	err := New[int](&panickingProducer{value: panicErr}).Run(context.Background())
+
+	var panicError *ProducerPanicError
+	if !errors.As(err, &panicError) {
+		t.Fatalf("expected a ProducerPanicError, got %v", err)
+	}
+	if !errors.Is(err, panicErr) {
+		t.Errorf("expected the panic error to be wrapped, got %v", err)
+	}
+	if len(panicError.Stack) == 0 {
+		t.Error("expected the stack of the producer")
+	}
+}
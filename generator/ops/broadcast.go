@@ -0,0 +1,355 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// SlowConsumerPolicy selects what happens when a subscriber of a [Broadcast] is not ready to receive a value.
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerBlock waits for the subscriber to receive the value, blocking all the subscribers.
+	SlowConsumerBlock SlowConsumerPolicy = iota
+
+	// SlowConsumerDrop discards the value for the subscriber, the other subscribers still receive it.
+	SlowConsumerDrop
+
+	// SlowConsumerDisconnect unsubscribes the subscriber, whose generator fails with
+	// [safeconcurrencyerrors.ErrSlowConsumer].
+	SlowConsumerDisconnect
+)
+
+// SubscribeOption configures a subscriber of a [Broadcast].
+type SubscribeOption func(*subscriberConfig)
+
+// subscriberConfig is configured by [SubscribeOption].
+type subscriberConfig struct {
+	policy SlowConsumerPolicy
+	buffer uint
+}
+
+// WithSlowConsumerPolicy selects what happens when the subscriber is not ready to receive a value, [SlowConsumerBlock]
+// by default.
+func WithSlowConsumerPolicy(policy SlowConsumerPolicy) SubscribeOption {
+	return func(c *subscriberConfig) {
+		c.policy = policy
+	}
+}
+
+// WithSubscriberBuffer buffers up to size values for the subscriber, before it is considered not ready to receive
+// values.
+func WithSubscriberBuffer(size uint) SubscribeOption {
+	return func(c *subscriberConfig) {
+		c.buffer = size
+	}
+}
+
+// Broadcast emits each value of a source generator to all its subscribers.
+// Subscribers may join and leave while the source is running, they only receive the values emitted after they
+// subscribed.
+// The source is consumed once the first subscriber joins, and it is canceled once all the subscribers have left.
+type Broadcast[T any] struct {
+	source types.Generator[T]
+	// autoStart starts the broadcast once the first subscriber is started, see [Tee] and [Share].
+	autoStart bool
+	// replay is the number of the last values emitted to the subscribers as they join, see [Replay].
+	replay  int
+	started *atomic.Bool
+	// cancel cancels the source with a cause.
+	cancel context.CancelCauseFunc
+	// joined is closed once the first subscriber joins.
+	joined chan struct{}
+	// done is closed once the source has completed, after err is set.
+	done chan struct{}
+	err  error
+
+	// mu protects the fields below.
+	mu          *sync.Mutex
+	subscribers map[*subscriber[T]]struct{}
+	// history holds the last values of the source, up to replay values.
+	history []T
+	// hasJoined is set once joined is closed.
+	hasJoined bool
+	// abandoned is set once all the subscribers have left, and the source is canceled.
+	abandoned bool
+	// closed is set once the source has completed.
+	closed bool
+}
+
+// NewBroadcast creates (but does not start) a [Broadcast] of the source generator.
+// The source must not be started, it is started with [Broadcast.Start].
+func NewBroadcast[T any](source types.Generator[T]) *Broadcast[T] {
+	return &Broadcast[T]{
+		source:      source,
+		started:     &atomic.Bool{},
+		cancel:      func(error) {},
+		joined:      make(chan struct{}),
+		done:        make(chan struct{}),
+		mu:          &sync.Mutex{},
+		subscribers: make(map[*subscriber[T]]struct{}),
+	}
+}
+
+// Tee creates n generators each emitting all the values of the source generator.
+// The source must not be started, it is started once the first of the returned generators is started, and it is
+// canceled once all of them are done.
+// See [Broadcast] for the subscribers joining and leaving dynamically.
+func Tee[T any](source types.Generator[T], n int, opts ...SubscribeOption) []types.Generator[T] {
+	b := NewBroadcast(source)
+	b.autoStart = true
+
+	gens := make([]types.Generator[T], n)
+	for i := range gens {
+		gens[i] = b.Subscribe(opts...)
+	}
+
+	return gens
+}
+
+// Share creates a [Broadcast] sharing a single run of the source generator between its subscribers.
+// The source must not be started, it is started once the first subscriber is started, and it is canceled once all
+// the subscribers are done.
+// [Broadcast.Start] must not be called.
+func Share[T any](source types.Generator[T]) *Broadcast[T] {
+	b := NewBroadcast(source)
+	b.autoStart = true
+
+	return b
+}
+
+// Replay creates a [Broadcast] like [Share], whose subscribers first receive the last n values of the source emitted
+// before they joined.
+// Once the source has completed, new subscribers receive the last n values followed by the error of the source.
+func Replay[T any](source types.Generator[T], n int) *Broadcast[T] {
+	b := Share(source)
+	b.replay = n
+
+	return b
+}
+
+// Start starts the source generator, which is consumed once the first subscriber joins.
+// It must be called exactly once.
+func (b *Broadcast[T]) Start(ctx context.Context) {
+	if b.started.Swap(true) {
+		panic("attempt to start previously started ops.Broadcast")
+	}
+
+	b.start(ctx)
+}
+
+// start starts the source generator, [Broadcast.started] must already be set.
+func (b *Broadcast[T]) start(ctx context.Context) {
+	b.mu.Lock()
+	sourceCtx, cancel := context.WithCancelCause(ctx)
+	b.cancel = cancel
+	// All the subscribers may have left before the broadcast was started.
+	if b.abandoned {
+		cancel(safeconcurrencyerrors.Stop)
+	}
+	b.mu.Unlock()
+
+	b.source.Start(sourceCtx)
+	go b.dispatch(sourceCtx)
+}
+
+// Wait waits for the source generator to complete, and returns its error.
+// The error is nil if the source was canceled because all the subscribers left.
+func (b *Broadcast[T]) Wait() error {
+	<-b.done
+
+	return b.err
+}
+
+// Subscribe creates (but does not start) a generator emitting the values of the source emitted from now on.
+// The subscriber leaves once the context passed to [types.Generator.Start] is canceled, or once the generator stops
+// being consumed.
+// The generator must be started and consumed, otherwise it will block the other subscribers with the default
+// [SlowConsumerBlock] policy.
+// Once the source has completed or all the subscribers have left, the generator fails with
+// [safeconcurrencyerrors.ErrBroadcastClosed], unless the values are replayed, see [Replay].
+func (b *Broadcast[T]) Subscribe(opts ...SubscribeOption) types.Generator[T] {
+	var config subscriberConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The values channel is large enough to hold the replayed values in addition to its buffer.
+	sub := &subscriber[T]{
+		broadcast: b,
+		config:    config,
+		values:    make(chan T, config.buffer+uint(len(b.history))),
+		left:      make(chan struct{}),
+		leaveOnce: &sync.Once{},
+	}
+	for _, value := range b.history {
+		sub.values <- value
+	}
+
+	switch {
+	case b.closed && b.replay > 0:
+		sub.err = b.err
+		close(sub.values)
+	case b.closed || b.abandoned:
+		sub.err = safeconcurrencyerrors.ErrBroadcastClosed
+		close(sub.values)
+	case !b.hasJoined:
+		b.hasJoined = true
+		close(b.joined)
+
+		fallthrough
+	default:
+		b.subscribers[sub] = struct{}{}
+	}
+
+	return generator.New[T](sub)
+}
+
+// remove unsubscribes the subscriber, and cancels the source once there are no more subscribers.
+func (b *Broadcast[T]) remove(sub *subscriber[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, sub)
+	if len(b.subscribers) == 0 && !b.closed {
+		b.abandoned = true
+		b.cancel(safeconcurrencyerrors.Stop)
+	}
+}
+
+// snapshot records the value in the history, and returns the current subscribers.
+// The subscribers joining later receive the value from the history instead.
+func (b *Broadcast[T]) snapshot(value T) []*subscriber[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.replay > 0 {
+		if len(b.history) == b.replay {
+			b.history = b.history[1:]
+		}
+		b.history = append(b.history, value)
+	}
+
+	subs := make([]*subscriber[T], 0, len(b.subscribers))
+	for sub := range b.subscribers {
+		subs = append(subs, sub)
+	}
+
+	return subs
+}
+
+// dispatch sends each value of the source to the subscribers, and completes the subscribers once the source is done.
+func (b *Broadcast[T]) dispatch(ctx context.Context) {
+	defer close(b.done)
+
+	select {
+	case <-ctx.Done():
+	case <-b.joined:
+		for value := range b.source.Results() {
+			for _, sub := range b.snapshot(value) {
+				b.send(sub, value)
+			}
+		}
+	}
+
+	err := generator.Drain(b.source)
+	if errors.Is(err, safeconcurrencyerrors.Stop) {
+		err = nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = err
+	b.closed = true
+	for sub := range b.subscribers {
+		sub.err = err
+		close(sub.values)
+	}
+}
+
+// send sends the value to the subscriber according to its [SlowConsumerPolicy].
+func (b *Broadcast[T]) send(sub *subscriber[T], value T) {
+	if sub.config.policy == SlowConsumerBlock {
+		select {
+		case <-sub.left:
+		case sub.values <- value:
+		}
+
+		return
+	}
+
+	select {
+	case <-sub.left:
+	case sub.values <- value:
+	default:
+		if sub.config.policy == SlowConsumerDisconnect {
+			b.mu.Lock()
+			_, subscribed := b.subscribers[sub]
+			b.mu.Unlock()
+			// The subscriber may have left concurrently, in which case its values are no longer consumed.
+			if subscribed {
+				sub.err = safeconcurrencyerrors.ErrSlowConsumer
+				close(sub.values)
+				b.remove(sub)
+			}
+		}
+	}
+}
+
+// subscriber implements [types.Producer] for the generators of the subscribers of a [Broadcast].
+type subscriber[T any] struct {
+	broadcast *Broadcast[T]
+	config    subscriberConfig
+	// values is closed by the broadcast, after err is set.
+	values chan T
+	err    error
+	// left is closed once the subscriber stops consuming values.
+	left      chan struct{}
+	leaveOnce *sync.Once
+}
+
+// Run implements [types.Producer.Run].
+func (sub *subscriber[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	// The source of a [Tee] is not bound to the context of any of its generators.
+	if sub.broadcast.autoStart && sub.broadcast.started.CompareAndSwap(false, true) {
+		sub.broadcast.start(context.Background())
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			sub.leave()
+
+			//nolint:wrapcheck
+			return context.Cause(ctx)
+		case value, ok := <-sub.values:
+			if !ok {
+				//nolint:wrapcheck
+				return sub.err
+			}
+			if err := emitter.Emit(ctx, value); err != nil {
+				sub.leave()
+
+				//nolint:wrapcheck
+				return err
+			}
+		}
+	}
+}
+
+// leave unsubscribes the subscriber from the broadcast.
+func (sub *subscriber[T]) leave() {
+	sub.leaveOnce.Do(func() {
+		close(sub.left)
+		sub.broadcast.remove(sub)
+	})
+}
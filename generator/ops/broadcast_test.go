@@ -0,0 +1,168 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+func TestTee(t *testing.T) {
+	gens := Tee(fromSlice(1, 2, 3), 3)
+
+	var wg sync.WaitGroup
+	for _, gen := range gens {
+		gen := gen
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			expectValues(t, gen, 1, 2, 3)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTeeCancelsSourceOnceAllDone(t *testing.T) {
+	canceled := make(chan error, 1)
+	// The source only completes once it is canceled.
+	source := Merge([]types.Generator[int]{
+		fromSlice(1, 2, 3),
+		generator.New[int](&blockingProducer[int]{canceled: canceled}),
+	})
+	gens := Tee(source, 2)
+
+	var wg sync.WaitGroup
+	for i, gen := range gens {
+		gen, n := gen, uint(i+1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values, err := collect(Take(gen, n))
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if len(values) != int(n) {
+				t.Errorf("Expected %d values, got %v", n, values)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cause := <-canceled; !errors.Is(cause, safeconcurrencyerrors.Stop) {
+		t.Errorf("Expected the source to be stopped, got %v", cause)
+	}
+}
+
+func TestBroadcastSlowConsumer(t *testing.T) {
+	testCases := []struct {
+		policy      SlowConsumerPolicy
+		expectedErr error
+	}{
+		{policy: SlowConsumerDrop, expectedErr: nil},
+		{policy: SlowConsumerDisconnect, expectedErr: safeconcurrencyerrors.ErrSlowConsumer},
+	}
+	for _, tc := range testCases {
+		b := NewBroadcast(fromSlice(1, 2, 3))
+		fast := b.Subscribe()
+		// The slow subscriber is not started until the source has completed, only its buffered value is received.
+		slow := b.Subscribe(WithSlowConsumerPolicy(tc.policy), WithSubscriberBuffer(1))
+		b.Start(context.Background())
+
+		expectValues(t, fast, 1, 2, 3)
+		if err := b.Wait(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		values, err := collect(slow)
+		if !errors.Is(err, tc.expectedErr) {
+			t.Errorf("Expected %v, got %v", tc.expectedErr, err)
+		}
+		if !reflect.DeepEqual(values, []int{1}) {
+			t.Errorf("Expected [1], got %v", values)
+		}
+	}
+}
+
+func TestBroadcastSourceError(t *testing.T) {
+	sourceErr := errors.New("source error")
+	b := NewBroadcast(failing(sourceErr, 1))
+	sub := b.Subscribe()
+	b.Start(context.Background())
+
+	values, err := collect(sub)
+	if !errors.Is(err, sourceErr) || !reflect.DeepEqual(values, []int{1}) {
+		t.Errorf("Expected [1] and the source error, got %v and %v", values, err)
+	}
+	if err := b.Wait(); !errors.Is(err, sourceErr) {
+		t.Errorf("Expected the source error, got %v", err)
+	}
+
+	if _, err := collect(b.Subscribe()); !errors.Is(err, safeconcurrencyerrors.ErrBroadcastClosed) {
+		t.Errorf("Expected ErrBroadcastClosed, got %v", err)
+	}
+}
+
+// chanProducer emits the values received from its channel until it is closed.
+type chanProducer[T any] struct {
+	values chan T
+}
+
+func (p *chanProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	for value := range p.values {
+		if err := emitter.Emit(ctx, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestShare(t *testing.T) {
+	s := Share(fromSlice(1, 2, 3))
+	expectValues(t, s.Subscribe(), 1, 2, 3)
+
+	if _, err := collect(s.Subscribe()); !errors.Is(err, safeconcurrencyerrors.ErrBroadcastClosed) {
+		t.Errorf("Expected ErrBroadcastClosed, got %v", err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	values := make(chan int)
+	r := Replay(generator.New[int](&chanProducer[int]{values: values}), 2)
+	first := r.Subscribe()
+	first.Start(context.Background())
+	for i := 1; i <= 3; i++ {
+		values <- i
+		if value := <-first.Results(); value != i {
+			t.Fatalf("Expected %d, got %d", i, value)
+		}
+	}
+
+	// The late subscriber first receives the last 2 values.
+	late := r.Subscribe()
+	late.Start(context.Background())
+	go func() {
+		values <- 4
+		close(values)
+	}()
+	for _, expected := range []int{2, 3, 4} {
+		if value := <-late.Results(); value != expected {
+			t.Errorf("Expected %d, got %d", expected, value)
+		}
+	}
+	if value := <-first.Results(); value != 4 {
+		t.Errorf("Expected 4, got %d", value)
+	}
+	for _, gen := range []types.Generator[int]{first, late} {
+		if err := generator.Drain(gen); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	// Once the source has completed, the last values are still replayed.
+	expectValues(t, r.Subscribe(), 3, 4)
+}
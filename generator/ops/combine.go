@@ -0,0 +1,273 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// CombineOption configures the operators combining multiple generators: [Merge], [MergeSorted], and [Concat].
+type CombineOption func(*combineConfig)
+
+// combineConfig is configured by [CombineOption].
+type combineConfig struct {
+	cancelOnError bool
+}
+
+// WithCancelOnError cancels the other generators once one of them fails.
+// By default, the other generators run until completion, and all the errors are returned.
+func WithCancelOnError() CombineOption {
+	return func(c *combineConfig) {
+		c.cancelOnError = true
+	}
+}
+
+// newCombineConfig applies the options.
+func newCombineConfig(opts []CombineOption) combineConfig {
+	var config combineConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return config
+}
+
+// siblingFailed is the cause of the cancellation of the generators whose sibling failed.
+// It wraps [safeconcurrencyerrors.Stop], so that the resulting errors are not returned by [joinErrors].
+func siblingFailed(err error) error {
+	return fmt.Errorf("%w: sibling generator failed: %w", safeconcurrencyerrors.Stop, err)
+}
+
+// joinErrors joins the errors of the generators, ignoring those caused by [safeconcurrencyerrors.Stop].
+func joinErrors(errs []error) error {
+	failures := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, safeconcurrencyerrors.Stop) {
+			failures = append(failures, err)
+		}
+	}
+
+	return errors.Join(failures...)
+}
+
+// Merge creates (but does not start) a [types.Generator] emitting the values of the sources as they are produced.
+// The sources must not be started, they are started concurrently with the returned generator.
+// The errors of all the sources are joined and returned from [types.Generator.Wait], see [WithCancelOnError].
+func Merge[T any](sources []types.Generator[T], opts ...CombineOption) types.Generator[T] {
+	return generator.New[T](&mergeProducer[T]{sources: sources, config: newCombineConfig(opts)})
+}
+
+// mergeProducer implements [types.Producer] for [Merge].
+type mergeProducer[T any] struct {
+	sources []types.Generator[T]
+	config  combineConfig
+}
+
+// Run implements [types.Producer.Run].
+func (p *mergeProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	sourcesCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(context.Canceled)
+
+	errs := make([]error, len(p.sources))
+	var wg sync.WaitGroup
+	for i, source := range p.sources {
+		i, source := i, source
+		source.Start(sourcesCtx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var emitErr error
+			for value := range source.Results() {
+				// Once downstream is canceled, so are the sources, which must still be drained.
+				if emitErr == nil {
+					emitErr = emitter.Emit(ctx, value)
+				}
+			}
+			errs[i] = source.Wait()
+			if errs[i] != nil && p.config.cancelOnError {
+				cancel(siblingFailed(errs[i]))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	return joinErrors(errs)
+}
+
+// Concat creates (but does not start) a [types.Generator] emitting the values of each source in turn.
+// The sources must not be started, each is started once the previous one completes.
+// The errors of all the sources are joined and returned from [types.Generator.Wait].
+// With [WithCancelOnError], the following sources are not started once a source fails.
+func Concat[T any](sources []types.Generator[T], opts ...CombineOption) types.Generator[T] {
+	return generator.New[T](&concatProducer[T]{sources: sources, config: newCombineConfig(opts)})
+}
+
+// concatProducer implements [types.Producer] for [Concat].
+type concatProducer[T any] struct {
+	sources []types.Generator[T]
+	config  combineConfig
+}
+
+// Run implements [types.Producer.Run].
+func (p *concatProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	errs := make([]error, 0, len(p.sources))
+	for _, source := range p.sources {
+		err := consume(ctx, source, emit[T], nil, emitter)
+		if ctxErr := context.Cause(ctx); ctxErr != nil {
+			//nolint:wrapcheck
+			return ctxErr
+		}
+		if err != nil {
+			errs = append(errs, err)
+			if p.config.cancelOnError {
+				break
+			}
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// Pair holds the values of two generators combined by [Zip].
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip creates (but does not start) a [types.Generator] emitting pairs of the successive values of the two generators.
+// The generators must not be started, they are started concurrently with the returned generator.
+// Once either generator completes, the other is canceled and its remaining values are discarded.
+// The errors of the two generators are joined and returned from [types.Generator.Wait].
+func Zip[A any, B any](first types.Generator[A], second types.Generator[B]) types.Generator[Pair[A, B]] {
+	return generator.New[Pair[A, B]](&zipProducer[A, B]{first: first, second: second})
+}
+
+// zipProducer implements [types.Producer] for [Zip].
+type zipProducer[A any, B any] struct {
+	first  types.Generator[A]
+	second types.Generator[B]
+}
+
+// Run implements [types.Producer.Run].
+func (p *zipProducer[A, B]) Run(ctx context.Context, emitter types.Emitter[Pair[A, B]]) error {
+	sourcesCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(context.Canceled)
+	p.first.Start(sourcesCtx)
+	p.second.Start(sourcesCtx)
+
+	for {
+		first, ok := <-p.first.Results()
+		if !ok {
+			break
+		}
+		second, ok := <-p.second.Results()
+		if !ok {
+			break
+		}
+		if err := emitter.Emit(ctx, Pair[A, B]{First: first, Second: second}); err != nil {
+			break
+		}
+	}
+
+	cancel(safeconcurrencyerrors.Stop)
+	errs := []error{generator.Drain(p.first), generator.Drain(p.second)}
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	return joinErrors(errs)
+}
+
+// MergeSorted creates (but does not start) a [types.Generator] emitting the values of the sources in the order given
+// by the less function, assuming each source is sorted in that order.
+// Equal values are emitted in the order of the sources.
+// The sources must not be started, they are started concurrently with the returned generator.
+// The errors of all the sources are joined and returned from [types.Generator.Wait], see [WithCancelOnError].
+// Without [WithCancelOnError], the values of the other sources are still emitted once a source fails.
+func MergeSorted[T any](
+	sources []types.Generator[T],
+	less func(T, T) bool,
+	opts ...CombineOption,
+) types.Generator[T] {
+	return generator.New[T](&mergeSortedProducer[T]{sources: sources, less: less, config: newCombineConfig(opts)})
+}
+
+// mergeSortedProducer implements [types.Producer] for [MergeSorted].
+type mergeSortedProducer[T any] struct {
+	sources []types.Generator[T]
+	less    func(T, T) bool
+	config  combineConfig
+}
+
+// Run implements [types.Producer.Run].
+func (p *mergeSortedProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	sourcesCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(context.Canceled)
+	for _, source := range p.sources {
+		source.Start(sourcesCtx)
+	}
+
+	heads := make([]T, len(p.sources))
+	live := make([]bool, len(p.sources))
+	done := make([]bool, len(p.sources))
+	errs := make([]error, len(p.sources))
+	failed := false
+	// next reads the next value of the source, or its error once it is exhausted.
+	next := func(i int) {
+		value, ok := <-p.sources[i].Results()
+		heads[i], live[i] = value, ok
+		if ok {
+			return
+		}
+		done[i] = true
+		errs[i] = p.sources[i].Wait()
+		if errs[i] != nil && p.config.cancelOnError {
+			failed = true
+			cancel(siblingFailed(errs[i]))
+		}
+	}
+	for i := range p.sources {
+		next(i)
+	}
+
+	for !failed {
+		selected := -1
+		for i := range p.sources {
+			if live[i] && (selected < 0 || p.less(heads[i], heads[selected])) {
+				selected = i
+			}
+		}
+		if selected < 0 {
+			break
+		}
+		if err := emitter.Emit(ctx, heads[selected]); err != nil {
+			break
+		}
+		next(selected)
+	}
+
+	cancel(safeconcurrencyerrors.Stop)
+	for i, source := range p.sources {
+		if !done[i] {
+			errs[i] = generator.Drain(source)
+		}
+	}
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	return joinErrors(errs)
+}
@@ -0,0 +1,125 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// blockingProducer emits nothing, and blocks until it is canceled.
+type blockingProducer[T any] struct {
+	canceled chan error
+}
+
+func (p *blockingProducer[T]) Run(ctx context.Context, _ types.Emitter[T]) error {
+	<-ctx.Done()
+	p.canceled <- context.Cause(ctx)
+
+	return context.Cause(ctx)
+}
+
+func failing[T any](err error, values ...T) types.Generator[T] {
+	return generator.New[T](&sliceProducer[T]{values: values, err: err})
+}
+
+func TestMerge(t *testing.T) {
+	values, err := collect(Merge([]types.Generator[int]{fromSlice(1, 2), fromSlice(3), fromSlice[int]()}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sort.Ints(values)
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", values)
+	}
+}
+
+func TestMergeJoinsErrors(t *testing.T) {
+	err1, err2 := errors.New("error 1"), errors.New("error 2")
+	values, err := collect(Merge([]types.Generator[int]{failing(err1, 1), failing[int](err2), fromSlice(2)}))
+	if !errors.Is(err, err1) || !errors.Is(err, err2) {
+		t.Errorf("Expected both errors, got %v", err)
+	}
+	if len(values) != 2 {
+		t.Errorf("Expected 2 values, got %v", values)
+	}
+}
+
+func TestMergeCancelOnError(t *testing.T) {
+	sourceErr := errors.New("source error")
+	canceled := make(chan error, 1)
+	blocking := generator.New[int](&blockingProducer[int]{canceled: canceled})
+
+	_, err := collect(Merge([]types.Generator[int]{blocking, failing[int](sourceErr)}, WithCancelOnError()))
+	if !errors.Is(err, sourceErr) {
+		t.Errorf("Expected the source error, got %v", err)
+	}
+	// The cancellation of the sibling is not reported as an error.
+	if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 1 {
+		t.Errorf("Expected a single joined error, got %v", err)
+	}
+	if cause := <-canceled; !errors.Is(cause, sourceErr) {
+		t.Errorf("Expected the sibling to be canceled by the source error, got %v", cause)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	expectValues(t, Concat([]types.Generator[int]{fromSlice(1, 2), fromSlice(3), fromSlice(4, 5)}), 1, 2, 3, 4, 5)
+
+	sourceErr := errors.New("source error")
+	values, err := collect(Concat([]types.Generator[int]{failing(sourceErr, 1), fromSlice(2)}))
+	if !errors.Is(err, sourceErr) || !reflect.DeepEqual(values, []int{1, 2}) {
+		t.Errorf("Expected [1 2] and the source error, got %v and %v", values, err)
+	}
+
+	// The following sources are not started.
+	values, err = collect(Concat([]types.Generator[int]{failing(sourceErr, 1), fromSlice(2)}, WithCancelOnError()))
+	if !errors.Is(err, sourceErr) || !reflect.DeepEqual(values, []int{1}) {
+		t.Errorf("Expected [1] and the source error, got %v and %v", values, err)
+	}
+}
+
+func TestZip(t *testing.T) {
+	canceled := make(chan error, 1)
+	longer := generator.New[string](&sliceProducer[string]{values: []string{"a", "b", "c", "d"}, canceled: canceled})
+	expectValues(t, Zip(fromSlice(1, 2), longer), Pair[int, string]{1, "a"}, Pair[int, string]{2, "b"})
+	if cause := <-canceled; !errors.Is(cause, safeconcurrencyerrors.Stop) {
+		t.Errorf("Expected the longer generator to be stopped, got %v", cause)
+	}
+
+	sourceErr := errors.New("source error")
+	if _, err := collect(Zip(failing(sourceErr, 1), fromSlice(1, 2))); !errors.Is(err, sourceErr) {
+		t.Errorf("Expected the source error, got %v", err)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	expectValues(t, MergeSorted([]types.Generator[int]{
+		fromSlice(1, 4, 7),
+		fromSlice[int](),
+		fromSlice(2, 5, 8, 9),
+		fromSlice(3, 6),
+	}, less), 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	sourceErr := errors.New("source error")
+	values, err := collect(MergeSorted([]types.Generator[int]{failing(sourceErr, 2), fromSlice(1, 3)}, less))
+	if !errors.Is(err, sourceErr) || !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3] and the source error, got %v and %v", values, err)
+	}
+
+	values, err = collect(MergeSorted(
+		[]types.Generator[int]{failing(sourceErr, 2), fromSlice(1, 3)},
+		less,
+		WithCancelOnError(),
+	))
+	// The values following the failure are not emitted.
+	if !errors.Is(err, sourceErr) || !reflect.DeepEqual(values, []int{1, 2}) {
+		t.Errorf("Expected [1 2] and the source error, got %v and %v", values, err)
+	}
+}
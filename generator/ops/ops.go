@@ -0,0 +1,124 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// step handles a value of the upstream generator, emitting zero or more values downstream.
+// It returns [safeconcurrencyerrors.Stop] to stop consuming the upstream generator without error.
+type step[T any, U any] func(ctx context.Context, value T, emitter types.Emitter[U]) error
+
+// flush emits the values remaining once the upstream generator is exhausted.
+type flush[U any] func(ctx context.Context, emitter types.Emitter[U]) error
+
+// tick emits the values due once the deadline of a timed operator expires.
+type tick[U any] func(ctx context.Context, now time.Time, emitter types.Emitter[U]) error
+
+// operator implements [types.Producer].
+// It starts the upstream generator, and applies the step to each of its values.
+type operator[T any, U any] struct {
+	upstream types.Generator[T]
+	step     step[T, U]
+	// flush may be nil.
+	flush flush[U]
+	// deadline and tick are only set for timed operators.
+	deadline *deadline
+	tick     tick[U]
+}
+
+// newOperator creates (but does not start) a generator applying the step to each value of the upstream generator.
+func newOperator[T any, U any](upstream types.Generator[T], stp step[T, U], fl flush[U]) types.Generator[U] {
+	return generator.New[U](&operator[T, U]{upstream: upstream, step: stp, flush: fl})
+}
+
+// newTimedOperator creates (but does not start) a generator applying the step to each value of the upstream generator,
+// and the tick each time the deadline expires.
+func newTimedOperator[T any, U any](
+	upstream types.Generator[T],
+	dl *deadline,
+	stp step[T, U],
+	tk tick[U],
+	fl flush[U],
+) types.Generator[U] {
+	return generator.New[U](&operator[T, U]{upstream: upstream, step: stp, flush: fl, deadline: dl, tick: tk})
+}
+
+// Run implements [types.Producer.Run].
+func (op *operator[T, U]) Run(ctx context.Context, emitter types.Emitter[U]) error {
+	return consumeTimed(ctx, op.upstream, op.deadline, op.step, op.tick, op.flush, emitter)
+}
+
+// consume starts the upstream generator, and applies the step to each of its values.
+// If the step fails or stops, the upstream generator is canceled and drained.
+// Upstream errors are returned unless the upstream generator was canceled by the step.
+func consume[T any, U any](
+	ctx context.Context,
+	upstream types.Generator[T],
+	stp step[T, U],
+	fl flush[U],
+	emitter types.Emitter[U],
+) error {
+	return consumeTimed(ctx, upstream, nil, stp, nil, fl, emitter)
+}
+
+// consumeTimed is like [consume], additionally applying the tick each time the deadline expires.
+// The deadline may be nil, in which case the tick is never applied.
+func consumeTimed[T any, U any](
+	ctx context.Context,
+	upstream types.Generator[T],
+	dl *deadline,
+	stp step[T, U],
+	tk tick[U],
+	fl flush[U],
+	emitter types.Emitter[U],
+) error {
+	upstreamCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(context.Canceled)
+	defer dl.stop()
+	upstream.Start(upstreamCtx)
+
+	var err error
+loop:
+	for {
+		select {
+		case value, ok := <-upstream.Results():
+			if !ok {
+				break loop
+			}
+			err = stp(ctx, value, emitter)
+		case now := <-dl.C():
+			dl.expired()
+			err = tk(ctx, now, emitter)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err != nil {
+		cancel(safeconcurrencyerrors.Stop)
+	}
+	// The upstream generator must be drained for it to return.
+	upstreamErr := generator.Drain(upstream)
+	if errors.Is(err, safeconcurrencyerrors.Stop) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if upstreamErr != nil {
+		return upstreamErr
+	}
+	if fl != nil {
+		if err := fl(ctx, emitter); err != nil && !errors.Is(err, safeconcurrencyerrors.Stop) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// sliceProducer emits its values, then returns its error.
+type sliceProducer[T any] struct {
+	values []T
+	err    error
+	// canceled receives the cause of the cancellation of the producer, if any.
+	canceled chan error
+}
+
+func (p *sliceProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	for _, value := range p.values {
+		if err := emitter.Emit(ctx, value); err != nil {
+			if p.canceled != nil {
+				p.canceled <- err
+			}
+
+			return err
+		}
+	}
+
+	return p.err
+}
+
+func fromSlice[T any](values ...T) types.Generator[T] {
+	return generator.New[T](&sliceProducer[T]{values: values})
+}
+
+// collect runs the generator and returns its values and error.
+func collect[T any](gen types.Generator[T]) ([]T, error) {
+	gen.Start(context.Background())
+
+	values := make([]T, 0)
+	for value := range gen.Results() {
+		values = append(values, value)
+	}
+
+	return values, gen.Wait()
+}
+
+func expectValues[T any](t *testing.T, gen types.Generator[T], expected ...T) {
+	t.Helper()
+
+	values, err := collect(gen)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected == nil {
+		expected = []T{}
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
+
+func double(_ context.Context, value int) (int, error) {
+	return value * 2, nil
+}
+
+func TestMap(t *testing.T) {
+	expectValues(t, Map(fromSlice(1, 2, 3), double), 2, 4, 6)
+	expectValues(t, Map(fromSlice(1, 2), func(_ context.Context, value int) (string, error) {
+		return strconv.Itoa(value), nil
+	}), "1", "2")
+}
+
+func TestMapError(t *testing.T) {
+	mapErr := errors.New("map error")
+	canceled := make(chan error, 1)
+	upstream := generator.New[int](&sliceProducer[int]{values: []int{1, 2, 3}, canceled: canceled})
+
+	values, err := collect(Map(upstream, func(_ context.Context, value int) (int, error) {
+		if value == 2 {
+			return 0, mapErr
+		}
+
+		return value, nil
+	}))
+	if !errors.Is(err, mapErr) {
+		t.Errorf("Expected the map error, got %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1}) {
+		t.Errorf("Expected [1], got %v", values)
+	}
+	if cause := <-canceled; !errors.Is(cause, safeconcurrencyerrors.Stop) {
+		t.Errorf("Expected the upstream generator to be stopped, got %v", cause)
+	}
+}
+
+func TestUpstreamError(t *testing.T) {
+	upstreamErr := errors.New("upstream error")
+	upstream := generator.New[int](&sliceProducer[int]{values: []int{1}, err: upstreamErr})
+
+	values, err := collect(Batch(Map(upstream, double), 2))
+	if !errors.Is(err, upstreamErr) {
+		t.Errorf("Expected the upstream error, got %v", err)
+	}
+	// The partial batch is not emitted on error.
+	if len(values) != 0 {
+		t.Errorf("Expected no values, got %v", values)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	expectValues(t, Filter(fromSlice(1, 2, 3, 4), func(_ context.Context, value int) (bool, error) {
+		return value%2 == 0, nil
+	}), 2, 4)
+}
+
+func TestFlatMap(t *testing.T) {
+	expectValues(t, FlatMap(fromSlice(1, 2, 3), func(_ context.Context, value int) (types.Generator[int], error) {
+		inner := make([]int, value)
+		for i := range inner {
+			inner[i] = value
+		}
+
+		return fromSlice(inner...), nil
+	}), 1, 2, 2, 3, 3, 3)
+}
+
+func TestTake(t *testing.T) {
+	canceled := make(chan error, 1)
+	upstream := generator.New[int](&sliceProducer[int]{values: []int{1, 2, 3, 4}, canceled: canceled})
+	expectValues(t, Take(upstream, 2), 1, 2)
+	if cause := <-canceled; !errors.Is(cause, safeconcurrencyerrors.Stop) {
+		t.Errorf("Expected the upstream generator to be stopped, got %v", cause)
+	}
+
+	expectValues(t, Take(fromSlice(1, 2), 5), 1, 2)
+	expectValues(t, Take(fromSlice(1, 2), 0))
+	// The upstream generator is not started, as it would never emit a value.
+	expectValues(t, Take(generator.New[int](&blockingProducer[int]{canceled: canceled}), 0))
+}
+
+func TestSkip(t *testing.T) {
+	expectValues(t, Skip(fromSlice(1, 2, 3), 2), 3)
+	expectValues(t, Skip(fromSlice(1, 2, 3), 5))
+}
+
+func TestBatch(t *testing.T) {
+	expectValues(t, Batch(fromSlice(1, 2, 3, 4, 5), 2), []int{1, 2}, []int{3, 4}, []int{5})
+	expectValues(t, Batch(fromSlice(1, 2), 2), []int{1, 2})
+}
+
+func TestDistinct(t *testing.T) {
+	expectValues(t, Distinct(fromSlice("a", "b", "a", "c", "b")), "a", "b", "c")
+}
+
+func TestScan(t *testing.T) {
+	expectValues(t, Scan(fromSlice(1, 2, 3), 10, func(_ context.Context, acc int, value int) (int, error) {
+		return acc + value, nil
+	}), 11, 13, 16)
+}
+
+func TestDownstreamCancellation(t *testing.T) {
+	canceled := make(chan error, 1)
+	upstream := generator.New[int](&sliceProducer[int]{values: []int{1, 2, 3}, canceled: canceled})
+	gen := Map(upstream, double)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gen.Start(ctx)
+	<-gen.Results()
+	cancel()
+	for range gen.Results() {
+		// Drain the results.
+	}
+	if err := gen.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if cause := <-canceled; cause == nil {
+		t.Error("Expected the upstream generator to be canceled")
+	}
+}
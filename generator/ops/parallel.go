@@ -0,0 +1,120 @@
+package ops
+
+import (
+	"context"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// ParallelMap creates (but does not start) a [types.Generator] executing the task created for each value of the
+// upstream generator in the [types.WorkerPool], and emitting the values of the tasks in the order of the upstream
+// values.
+// The upstream generator must not be started, it is started with the returned generator.
+//
+// At most window tasks are submitted or awaiting emission at any time, bounding the memory used to reorder the values.
+// Once the window is full, the upstream generator is no longer consumed until the oldest task is emitted.
+//
+// If a task fails, the upstream generator and the other tasks are canceled, and the error of the first failed task in
+// the order of the upstream values is returned from [types.Generator.Wait].
+func ParallelMap[T any, ResourceT any, U any](
+	upstream types.Generator[T],
+	pool types.WorkerPool[ResourceT],
+	window uint,
+	newTask func(T) types.Task[ResourceT, U],
+) types.Generator[U] {
+	if window == 0 {
+		panic("parallel map window must be greater than 0")
+	}
+
+	return generator.New[U](&parallelMapProducer[T, ResourceT, U]{
+		upstream: upstream,
+		pool:     pool,
+		window:   window,
+		newTask:  newTask,
+	})
+}
+
+// parallelMapProducer implements [types.Producer] for [ParallelMap].
+type parallelMapProducer[T any, ResourceT any, U any] struct {
+	upstream types.Generator[T]
+	pool     types.WorkerPool[ResourceT]
+	window   uint
+	newTask  func(T) types.Task[ResourceT, U]
+}
+
+// Run implements [types.Producer.Run].
+func (p *parallelMapProducer[T, ResourceT, U]) Run(ctx context.Context, emitter types.Emitter[U]) error {
+	tasksCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(context.Canceled)
+	p.upstream.Start(tasksCtx)
+
+	// slots bounds the number of tasks submitted and not yet emitted.
+	slots := make(chan struct{}, p.window)
+	// pending holds the results of the submitted tasks in the order of the upstream values.
+	// It is closed once the upstream generator has completed, after upstreamErr is set.
+	pending := make(chan types.TaskResult[U], p.window)
+	var upstreamErr error
+	go func() {
+		defer close(pending)
+		p.submit(tasksCtx, slots, pending)
+		upstreamErr = generator.Drain(p.upstream)
+	}()
+
+	var err error
+	for result := range pending {
+		value, ok := <-result.Results()
+		if err = result.Drain(); err == nil && ok {
+			err = emitter.Emit(ctx, value)
+		}
+		if err != nil {
+			break
+		}
+		<-slots
+	}
+	if err != nil {
+		cancel(safeconcurrencyerrors.Stop)
+		for result := range pending {
+			_ = result.Drain()
+		}
+	}
+
+	if ctxErr := context.Cause(ctx); ctxErr != nil {
+		//nolint:wrapcheck
+		return ctxErr
+	}
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	//nolint:wrapcheck
+	return upstreamErr
+}
+
+// submit submits a task for each upstream value until the upstream generator completes or the context is canceled.
+func (p *parallelMapProducer[T, ResourceT, U]) submit(
+	ctx context.Context,
+	slots chan<- struct{},
+	pending chan<- types.TaskResult[U],
+) {
+	for value := range p.upstream.Results() {
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+
+		valuelessTask, result := task.Wrap[ResourceT, U](ctx, p.newTask(value))
+		valuelessTask = task.Awaited(valuelessTask)
+		select {
+		case <-ctx.Done():
+			return
+		case p.pool.Requests() <- valuelessTask:
+		}
+		// The window is never exceeded, so this does not block.
+		pending <- result
+	}
+}
@@ -0,0 +1,145 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+)
+
+// gatedTask doubles its value once its gate is closed.
+type gatedTask struct {
+	value    int
+	gate     chan struct{}
+	started  chan<- int
+	inFlight *atomic.Int32
+	maxed    *atomic.Int32
+}
+
+func (t *gatedTask) Execute(ctx context.Context, _ any) (int, error) {
+	n := t.inFlight.Add(1)
+	for m := t.maxed.Load(); n > m && !t.maxed.CompareAndSwap(m, n); m = t.maxed.Load() {
+		// Retry until the maximum is at least n.
+	}
+	defer t.inFlight.Add(-1)
+
+	t.started <- t.value
+	select {
+	case <-ctx.Done():
+		return 0, context.Cause(ctx)
+	case <-t.gate:
+		return t.value * 2, nil
+	}
+}
+
+// gatedTasks creates a gate for each value, and a function creating the task for the value.
+func gatedTasks(values []int) ([]chan struct{}, chan int, *atomic.Int32, func(int) types.Task[any, int]) {
+	gates := make([]chan struct{}, len(values))
+	for i := range gates {
+		gates[i] = make(chan struct{})
+	}
+	started := make(chan int, len(values))
+	var inFlight, maxed atomic.Int32
+
+	return gates, started, &maxed, func(value int) types.Task[any, int] {
+		return &gatedTask{value: value, gate: gates[value], started: started, inFlight: &inFlight, maxed: &maxed}
+	}
+}
+
+// failingTask returns its value, or its error if it fails.
+type failingTask struct {
+	value int
+	fail  bool
+	err   error
+}
+
+func (t *failingTask) Execute(_ context.Context, _ any) (int, error) {
+	if t.fail {
+		return 0, t.err
+	}
+
+	return t.value, nil
+}
+
+func startPool(t *testing.T, concurrency int) types.WorkerPool[any] {
+	t.Helper()
+
+	p := workpool.New[any](nil, concurrency)
+	p.Start()
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func TestParallelMapOrder(t *testing.T) {
+	p := startPool(t, 4)
+	gates, started, _, newTask := gatedTasks([]int{0, 1, 2, 3})
+
+	gen := ParallelMap(fromSlice(0, 1, 2, 3), p, 4, newTask)
+	gen.Start(context.Background())
+	for range gates {
+		<-started
+	}
+	// The tasks complete in the reverse order.
+	for i := len(gates) - 1; i >= 0; i-- {
+		close(gates[i])
+	}
+
+	values := make([]int, 0)
+	for value := range gen.Results() {
+		values = append(values, value)
+	}
+	if err := gen.Wait(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{0, 2, 4, 6}) {
+		t.Errorf("Expected [0 2 4 6], got %v", values)
+	}
+}
+
+func TestParallelMapWindow(t *testing.T) {
+	p := startPool(t, 4)
+	upstream := []int{0, 1, 2, 3, 4, 5}
+	gates, started, maxed, newTask := gatedTasks(upstream)
+
+	gen := ParallelMap(fromSlice(upstream...), p, 2, newTask)
+	gen.Start(context.Background())
+	go func() {
+		for range upstream {
+			close(gates[<-started])
+		}
+	}()
+
+	values := make([]int, 0)
+	for value := range gen.Results() {
+		values = append(values, value)
+	}
+	if err := gen.Wait(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{0, 2, 4, 6, 8, 10}) {
+		t.Errorf("Expected [0 2 4 6 8 10], got %v", values)
+	}
+	if n := maxed.Load(); n > 2 {
+		t.Errorf("Expected at most 2 tasks in flight, got %d", n)
+	}
+}
+
+func TestParallelMapError(t *testing.T) {
+	p := startPool(t, 2)
+	taskErr := errors.New("task error")
+
+	values, err := collect(ParallelMap(fromSlice(1, 2, 3, 4), p, 2, func(value int) types.Task[any, int] {
+		return &failingTask{value: value, fail: value == 2, err: taskErr}
+	}))
+	if !errors.Is(err, taskErr) {
+		t.Errorf("Expected the task error, got %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1}) {
+		t.Errorf("Expected [1], got %v", values)
+	}
+}
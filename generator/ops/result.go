@@ -0,0 +1,69 @@
+package ops
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// SkipErrors creates (but does not start) a [types.Generator] emitting the values of the successful results of the
+// upstream generator, discarding the failed items.
+// See [Map] for details on the lifecycle of the upstream generator.
+func SkipErrors[T any](upstream types.Generator[types.Result[T]]) types.Generator[T] {
+	return newOperator(upstream, func(ctx context.Context, result types.Result[T], emitter types.Emitter[T]) error {
+		if result.Err != nil {
+			return nil
+		}
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, result.Value)
+	}, nil)
+}
+
+// CollectErrors creates (but does not start) a [types.Generator] emitting the values of the successful results of the
+// upstream generator.
+// The errors of the failed items are joined with the error of the upstream generator, if any, and returned from
+// [types.Generator.Wait].
+// See [Map] for details on the lifecycle of the upstream generator.
+func CollectErrors[T any](upstream types.Generator[types.Result[T]]) types.Generator[T] {
+	return generator.New[T](&collectErrorsProducer[T]{upstream: upstream})
+}
+
+// collectErrorsProducer implements [types.Producer] for [CollectErrors].
+type collectErrorsProducer[T any] struct {
+	upstream types.Generator[types.Result[T]]
+}
+
+// Run implements [types.Producer.Run].
+func (p *collectErrorsProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	var errs []error
+	err := consume(ctx, p.upstream, func(ctx context.Context, result types.Result[T], emitter types.Emitter[T]) error {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+
+			return nil
+		}
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, result.Value)
+	}, nil, emitter)
+
+	return errors.Join(append(errs, err)...)
+}
+
+// AbortOnError creates (but does not start) a [types.Generator] emitting the values of the successful results of the
+// upstream generator, until an item fails.
+// The upstream generator is then canceled, and the error of the item is returned from [types.Generator.Wait].
+// See [Map] for details on the lifecycle of the upstream generator.
+func AbortOnError[T any](upstream types.Generator[types.Result[T]]) types.Generator[T] {
+	return newOperator(upstream, func(ctx context.Context, result types.Result[T], emitter types.Emitter[T]) error {
+		if result.Err != nil {
+			return result.Err
+		}
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, result.Value)
+	}, nil)
+}
@@ -0,0 +1,69 @@
+package ops
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// itemResults returns results of the values, failing the items equal to 0 with the error.
+func itemResults(itemErr error, values ...int) []types.Result[int] {
+	results := make([]types.Result[int], len(values))
+	for i, value := range values {
+		if value == 0 {
+			results[i].Err = itemErr
+		} else {
+			results[i].Value = value
+		}
+	}
+
+	return results
+}
+
+func TestSkipErrors(t *testing.T) {
+	itemErr := errors.New("item error")
+	expectValues(t, SkipErrors(fromSlice(itemResults(itemErr, 1, 0, 2, 0)...)), 1, 2)
+}
+
+func TestCollectErrors(t *testing.T) {
+	itemErr1, itemErr2 := errors.New("item error 1"), errors.New("item error 2")
+	results := append(itemResults(itemErr1, 1, 0), itemResults(itemErr2, 2, 0)...)
+
+	values, err := collect(CollectErrors(fromSlice(results...)))
+	if !errors.Is(err, itemErr1) || !errors.Is(err, itemErr2) {
+		t.Errorf("Expected both item errors, got %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1, 2}) {
+		t.Errorf("Expected [1 2], got %v", values)
+	}
+
+	upstreamErr := errors.New("upstream error")
+	_, err = collect(CollectErrors(failing(upstreamErr, itemResults(itemErr1, 0)...)))
+	if !errors.Is(err, itemErr1) || !errors.Is(err, upstreamErr) {
+		t.Errorf("Expected the item and upstream errors, got %v", err)
+	}
+}
+
+func TestAbortOnError(t *testing.T) {
+	itemErr := errors.New("item error")
+	canceled := make(chan error, 1)
+	upstream := generator.New[types.Result[int]](&sliceProducer[types.Result[int]]{
+		values:   itemResults(itemErr, 1, 0, 2),
+		canceled: canceled,
+	})
+
+	values, err := collect(AbortOnError(upstream))
+	if !errors.Is(err, itemErr) {
+		t.Errorf("Expected the item error, got %v", err)
+	}
+	if !reflect.DeepEqual(values, []int{1}) {
+		t.Errorf("Expected [1], got %v", values)
+	}
+	if cause := <-canceled; !errors.Is(cause, safeconcurrencyerrors.Stop) {
+		t.Errorf("Expected the upstream generator to be stopped, got %v", cause)
+	}
+}
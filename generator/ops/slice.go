@@ -0,0 +1,97 @@
+package ops
+
+import (
+	"context"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+// Take creates (but does not start) a [types.Generator] emitting the first n values of the upstream generator.
+// The upstream generator is canceled once the n-th value is emitted, it is never started if n is 0.
+// See [Map] for details on the lifecycle of the upstream generator.
+func Take[T any](upstream types.Generator[T], n uint) types.Generator[T] {
+	if n == 0 {
+		return generator.New[T](emptyProducer[T]{})
+	}
+
+	var taken uint
+
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[T]) error {
+		if taken >= n {
+			return safeconcurrencyerrors.Stop
+		}
+		if err := emitter.Emit(ctx, value); err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+		taken++
+		if taken >= n {
+			return safeconcurrencyerrors.Stop
+		}
+
+		return nil
+	}, nil)
+}
+
+// emptyProducer implements [types.Producer], emitting no value.
+type emptyProducer[T any] struct{}
+
+// Run implements [types.Producer.Run].
+func (emptyProducer[T]) Run(context.Context, types.Emitter[T]) error {
+	return nil
+}
+
+// Skip creates (but does not start) a [types.Generator] emitting the values of the upstream generator after the first
+// n values.
+// See [Map] for details on the lifecycle of the upstream generator.
+func Skip[T any](upstream types.Generator[T], n uint) types.Generator[T] {
+	var skipped uint
+
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[T]) error {
+		if skipped < n {
+			skipped++
+
+			return nil
+		}
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, value)
+	}, nil)
+}
+
+// Batch creates (but does not start) a [types.Generator] emitting the values of the upstream generator in slices of
+// size values.
+// The last slice may be shorter, it is only emitted if the upstream generator completes without error.
+// See [Map] for details on the lifecycle of the upstream generator.
+func Batch[T any](upstream types.Generator[T], size uint) types.Generator[[]T] {
+	if size == 0 {
+		panic("batch size must be greater than 0")
+	}
+
+	var batch []T
+
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[[]T]) error {
+		if batch == nil {
+			batch = make([]T, 0, size)
+		}
+		batch = append(batch, value)
+		if uint(len(batch)) < size {
+			return nil
+		}
+
+		full := batch
+		batch = nil
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, full)
+	}, func(ctx context.Context, emitter types.Emitter[[]T]) error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, batch)
+	})
+}
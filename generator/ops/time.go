@@ -0,0 +1,359 @@
+package ops
+
+import (
+	"context"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+)
+
+// TimeOption configures the time-based operators.
+type TimeOption func(*timeConfig)
+
+// timeConfig is configured by [TimeOption].
+type timeConfig struct {
+	clock types.Clock
+}
+
+// WithClock sets the [types.Clock] of the operator, the real clock by default.
+// See [github.com/Izzette/go-safeconcurrency/clock.NewFake] to test the operators deterministically.
+func WithClock(clk types.Clock) TimeOption {
+	return func(c *timeConfig) {
+		c.clock = clk
+	}
+}
+
+// newTimeConfig applies the options.
+func newTimeConfig(opts []TimeOption) timeConfig {
+	var config timeConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	config.clock = clock.OrReal(config.clock)
+
+	return config
+}
+
+// deadline is a [types.Timer] which is only created once it is first reset.
+// Its methods are not concurrency-safe, it is only used by the goroutine running the operator.
+type deadline struct {
+	clock types.Clock
+	timer types.Timer
+	armed bool
+}
+
+// newDeadline creates a deadline which is not armed.
+func newDeadline(clk types.Clock) *deadline {
+	return &deadline{clock: clk}
+}
+
+// C returns the channel of the timer, or nil if the deadline is nil or not armed.
+func (d *deadline) C() <-chan time.Time {
+	if d == nil || !d.armed {
+		return nil
+	}
+
+	return d.timer.C()
+}
+
+// reset arms the deadline to expire after the duration.
+func (d *deadline) reset(dur time.Duration) {
+	d.stop()
+	if d.timer == nil {
+		d.timer = d.clock.NewTimer(dur)
+	} else {
+		d.timer.Reset(dur)
+	}
+	d.armed = true
+}
+
+// stop disarms the deadline, discarding the expiry if it was not received.
+// It may be called on a nil deadline.
+func (d *deadline) stop() {
+	if d == nil || !d.armed {
+		return
+	}
+	if !d.timer.Stop() {
+		select {
+		case <-d.timer.C():
+		default:
+		}
+	}
+	d.armed = false
+}
+
+// expired must be called once the expiry was received from [deadline.C].
+func (d *deadline) expired() {
+	d.armed = false
+}
+
+// Debounce creates (but does not start) a [types.Generator] emitting a value of the upstream generator only once no
+// other value was produced for the duration.
+// The pending value is emitted once the upstream generator completes without error.
+// See [Map] for details on the lifecycle of the upstream generator.
+func Debounce[T any](upstream types.Generator[T], d time.Duration, opts ...TimeOption) types.Generator[T] {
+	dl := newDeadline(newTimeConfig(opts).clock)
+	var pending T
+	var hasPending bool
+	emitPending := func(ctx context.Context, emitter types.Emitter[T]) error {
+		if !hasPending {
+			return nil
+		}
+		hasPending = false
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, pending)
+	}
+
+	return newTimedOperator(upstream, dl, func(_ context.Context, value T, _ types.Emitter[T]) error {
+		pending, hasPending = value, true
+		dl.reset(d)
+
+		return nil
+	}, func(ctx context.Context, _ time.Time, emitter types.Emitter[T]) error {
+		return emitPending(ctx, emitter)
+	}, emitPending)
+}
+
+// Throttle creates (but does not start) a [types.Generator] emitting a value of the upstream generator, then
+// discarding the following values for the duration.
+// See [Map] for details on the lifecycle of the upstream generator.
+func Throttle[T any](upstream types.Generator[T], d time.Duration, opts ...TimeOption) types.Generator[T] {
+	clk := newTimeConfig(opts).clock
+	var until time.Time
+
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[T]) error {
+		now := clk.Now()
+		if now.Before(until) {
+			return nil
+		}
+		until = now.Add(d)
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, value)
+	}, nil)
+}
+
+// Sample creates (but does not start) a [types.Generator] emitting the latest value of the upstream generator at most
+// once per duration.
+// The period starts with the first value following the previous sample, the latest value is emitted once it ends.
+// The value pending when the upstream generator completes is discarded.
+// See [Map] for details on the lifecycle of the upstream generator.
+func Sample[T any](upstream types.Generator[T], d time.Duration, opts ...TimeOption) types.Generator[T] {
+	dl := newDeadline(newTimeConfig(opts).clock)
+	var latest T
+
+	return newTimedOperator(upstream, dl, func(_ context.Context, value T, _ types.Emitter[T]) error {
+		latest = value
+		if !dl.armed {
+			dl.reset(d)
+		}
+
+		return nil
+	}, func(ctx context.Context, _ time.Time, emitter types.Emitter[T]) error {
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, latest)
+	}, nil)
+}
+
+// BufferTime creates (but does not start) a [types.Generator] emitting the values of the upstream generator in slices,
+// once the duration has passed since the first value of the slice, or once the slice holds maxSize values.
+// A maxSize of 0 does not limit the size of the slices.
+// The last slice is emitted once the upstream generator completes without error.
+// See [Map] for details on the lifecycle of the upstream generator.
+func BufferTime[T any](
+	upstream types.Generator[T],
+	d time.Duration,
+	maxSize uint,
+	opts ...TimeOption,
+) types.Generator[[]T] {
+	if d <= 0 {
+		panic("buffer duration must be greater than 0")
+	}
+
+	dl := newDeadline(newTimeConfig(opts).clock)
+	var batch []T
+	emitBatch := func(ctx context.Context, emitter types.Emitter[[]T]) error {
+		dl.stop()
+		if len(batch) == 0 {
+			return nil
+		}
+		full := batch
+		batch = nil
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, full)
+	}
+
+	return newTimedOperator(upstream, dl, func(ctx context.Context, value T, emitter types.Emitter[[]T]) error {
+		batch = append(batch, value)
+		if len(batch) == 1 {
+			dl.reset(d)
+		}
+		if maxSize > 0 && uint(len(batch)) >= maxSize {
+			return emitBatch(ctx, emitter)
+		}
+
+		return nil
+	}, func(ctx context.Context, _ time.Time, emitter types.Emitter[[]T]) error {
+		return emitBatch(ctx, emitter)
+	}, emitBatch)
+}
+
+// Window holds the values of the upstream generator whose time is within [Window.Start, Window.End).
+type Window[T any] struct {
+	Start  time.Time
+	End    time.Time
+	Values []T
+}
+
+// TumblingWindows creates (but does not start) a [types.Generator] emitting the values of the upstream generator
+// grouped into consecutive windows of the size.
+// See [SlidingWindows] for details on the time of the values and the emission of the windows.
+func TumblingWindows[T any](
+	upstream types.Generator[T],
+	size time.Duration,
+	eventTime func(T) time.Time,
+	opts ...TimeOption,
+) types.Generator[Window[T]] {
+	return SlidingWindows(upstream, size, size, eventTime, opts...)
+}
+
+// SlidingWindows creates (but does not start) a [types.Generator] emitting the values of the upstream generator
+// grouped into windows of the size, starting every slide.
+// The windows are aligned to multiples of the slide since the zero time, see [time.Time.Truncate].
+// Windows without values are not emitted.
+//
+// The time of each value is given by eventTime, or it is the time at which the value was received if eventTime is nil.
+// With arrival time, each window is emitted once the clock reaches its end.
+// With event time, each window is emitted once a value whose time is at or after its end is received, and the values
+// belonging to windows which were already emitted are discarded.
+// The remaining windows are emitted in order once the upstream generator completes without error.
+// See [Map] for details on the lifecycle of the upstream generator.
+func SlidingWindows[T any](
+	upstream types.Generator[T],
+	size time.Duration,
+	slide time.Duration,
+	eventTime func(T) time.Time,
+	opts ...TimeOption,
+) types.Generator[Window[T]] {
+	if size <= 0 || slide <= 0 {
+		panic("window size and slide must be greater than 0")
+	}
+
+	clk := newTimeConfig(opts).clock
+	w := &windower[T]{size: size, slide: slide}
+	var dl *deadline
+	if eventTime == nil {
+		dl = newDeadline(clk)
+	}
+	// rearm arms the deadline for the end of the earliest window, when using arrival time.
+	rearm := func() {
+		if dl == nil {
+			return
+		}
+		if len(w.open) == 0 {
+			dl.stop()
+
+			return
+		}
+		dl.reset(w.open[0].End.Sub(clk.Now()))
+	}
+
+	return newTimedOperator(upstream, dl, func(ctx context.Context, value T, emitter types.Emitter[Window[T]]) error {
+		var ts time.Time
+		if eventTime != nil {
+			ts = eventTime(value)
+			if ts.After(w.watermark) {
+				w.watermark = ts
+			}
+		} else {
+			ts = clk.Now()
+		}
+		w.add(ts, value)
+		if eventTime != nil {
+			return w.emitClosed(ctx, w.watermark, emitter)
+		}
+		rearm()
+
+		return nil
+	}, func(ctx context.Context, now time.Time, emitter types.Emitter[Window[T]]) error {
+		if err := w.emitClosed(ctx, now, emitter); err != nil {
+			return err
+		}
+		rearm()
+
+		return nil
+	}, func(ctx context.Context, emitter types.Emitter[Window[T]]) error {
+		for len(w.open) > 0 {
+			window := w.open[0]
+			w.open = w.open[1:]
+			if err := emitter.Emit(ctx, *window); err != nil {
+				//nolint:wrapcheck
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// windower holds the open windows of [SlidingWindows].
+type windower[T any] struct {
+	size  time.Duration
+	slide time.Duration
+	// open holds the windows which were not emitted, in order of their start.
+	open []*Window[T]
+	// watermark is the latest time of the values, when using event time.
+	watermark time.Time
+	// closed is the time until which the windows were emitted, the windows ending before it are discarded.
+	closed time.Time
+}
+
+// add adds the value to all the open windows containing its time.
+func (w *windower[T]) add(ts time.Time, value T) {
+	for start := ts.Truncate(w.slide); start.Add(w.size).After(ts); start = start.Add(-w.slide) {
+		end := start.Add(w.size)
+		if !end.After(w.closed) {
+			break
+		}
+		window := w.window(start, end)
+		window.Values = append(window.Values, value)
+	}
+}
+
+// window returns the open window starting at the time, creating it if needed.
+func (w *windower[T]) window(start time.Time, end time.Time) *Window[T] {
+	i := len(w.open)
+	for i > 0 && !w.open[i-1].Start.Before(start) {
+		if w.open[i-1].Start.Equal(start) {
+			return w.open[i-1]
+		}
+		i--
+	}
+
+	window := &Window[T]{Start: start, End: end}
+	w.open = append(w.open, nil)
+	copy(w.open[i+1:], w.open[i:])
+	w.open[i] = window
+
+	return window
+}
+
+// emitClosed emits the windows ending at or before the time, in order.
+func (w *windower[T]) emitClosed(ctx context.Context, now time.Time, emitter types.Emitter[Window[T]]) error {
+	for len(w.open) > 0 && !w.open[0].End.After(now) {
+		window := w.open[0]
+		w.open = w.open[1:]
+		if err := emitter.Emit(ctx, *window); err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+	}
+	if now.After(w.closed) {
+		w.closed = now
+	}
+
+	return nil
+}
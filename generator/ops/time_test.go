@@ -0,0 +1,231 @@
+package ops
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+//nolint:gochecknoglobals
+var epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// testClock wraps the fake clock, notifying each time a timer is armed or the time is read.
+type testClock struct {
+	*clock.Fake
+	armed chan time.Duration
+	read  chan struct{}
+}
+
+func newTestClock() *testClock {
+	return &testClock{
+		Fake:  clock.NewFake(epoch),
+		armed: make(chan time.Duration, 100),
+		read:  make(chan struct{}, 100),
+	}
+}
+
+func (c *testClock) Now() time.Time {
+	now := c.Fake.Now()
+	select {
+	case c.read <- struct{}{}:
+	default:
+	}
+
+	return now
+}
+
+func (c *testClock) NewTimer(d time.Duration) types.Timer {
+	timer := c.Fake.NewTimer(d)
+	c.armed <- d
+
+	return &testTimer{Timer: timer, clock: c}
+}
+
+type testTimer struct {
+	types.Timer
+	clock *testClock
+}
+
+func (t *testTimer) Reset(d time.Duration) bool {
+	active := t.Timer.Reset(d)
+	t.clock.armed <- d
+
+	return active
+}
+
+// pushProducer emits the values pushed by the test.
+type pushProducer[T any] struct {
+	values  chan T
+	emitted chan struct{}
+}
+
+func (p *pushProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	for value := range p.values {
+		if err := emitter.Emit(ctx, value); err != nil {
+			return err
+		}
+		p.emitted <- struct{}{}
+	}
+
+	return nil
+}
+
+// push returns once the value was received by the downstream operator.
+func (p *pushProducer[T]) push(value T) {
+	p.values <- value
+	<-p.emitted
+}
+
+func newPushProducer[T any]() (*pushProducer[T], types.Generator[T]) {
+	p := &pushProducer[T]{values: make(chan T), emitted: make(chan struct{})}
+
+	return p, generator.New[T](p)
+}
+
+// finish closes the upstream, and expects the remaining values of the generator.
+func finish[T any, U any](t *testing.T, p *pushProducer[T], gen types.Generator[U], expected ...U) {
+	t.Helper()
+
+	close(p.values)
+	values := make([]U, 0)
+	for value := range gen.Results() {
+		values = append(values, value)
+	}
+	if err := gen.Wait(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected == nil {
+		expected = []U{}
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
+
+func expectNext[T any](t *testing.T, gen types.Generator[T], expected T) {
+	t.Helper()
+
+	if value := <-gen.Results(); !reflect.DeepEqual(value, expected) {
+		t.Errorf("Expected %v, got %v", expected, value)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	clk := newTestClock()
+	p, upstream := newPushProducer[int]()
+	gen := Debounce(upstream, time.Second, WithClock(clk))
+	gen.Start(context.Background())
+
+	p.push(1)
+	<-clk.armed
+	clk.Advance(500 * time.Millisecond)
+	p.push(2)
+	<-clk.armed
+	clk.Advance(500 * time.Millisecond)
+	clk.Advance(500 * time.Millisecond)
+	expectNext(t, gen, 2)
+
+	// The pending value is emitted once the upstream completes.
+	p.push(3)
+	<-clk.armed
+	finish(t, p, gen, 3)
+}
+
+func TestThrottle(t *testing.T) {
+	clk := newTestClock()
+	p, upstream := newPushProducer[int]()
+	gen := Throttle(upstream, time.Second, WithClock(clk))
+	gen.Start(context.Background())
+
+	p.push(1)
+	expectNext(t, gen, 1)
+	p.push(2)
+	<-clk.read
+	<-clk.read
+	clk.Advance(time.Second)
+	p.push(3)
+	expectNext(t, gen, 3)
+	finish(t, p, gen)
+}
+
+func TestSample(t *testing.T) {
+	clk := newTestClock()
+	p, upstream := newPushProducer[int]()
+	gen := Sample(upstream, time.Second, WithClock(clk))
+	gen.Start(context.Background())
+
+	p.push(1)
+	<-clk.armed
+	p.push(2)
+	clk.Advance(time.Second)
+	expectNext(t, gen, 2)
+
+	// The pending value is discarded once the upstream completes.
+	p.push(3)
+	<-clk.armed
+	finish(t, p, gen)
+}
+
+func TestBufferTime(t *testing.T) {
+	clk := newTestClock()
+	p, upstream := newPushProducer[int]()
+	gen := BufferTime(upstream, time.Second, 3, WithClock(clk))
+	gen.Start(context.Background())
+
+	p.push(1)
+	<-clk.armed
+	p.push(2)
+	clk.Advance(time.Second)
+	expectNext(t, gen, []int{1, 2})
+
+	p.push(3)
+	<-clk.armed
+	p.push(4)
+	p.push(5)
+	expectNext(t, gen, []int{3, 4, 5})
+
+	p.push(6)
+	<-clk.armed
+	finish(t, p, gen, []int{6})
+}
+
+func TestTumblingWindowsArrivalTime(t *testing.T) {
+	clk := newTestClock()
+	p, upstream := newPushProducer[int]()
+	gen := TumblingWindows(upstream, time.Second, nil, WithClock(clk))
+	gen.Start(context.Background())
+
+	p.push(1)
+	<-clk.armed
+	clk.Advance(500 * time.Millisecond)
+	p.push(2)
+	<-clk.armed
+	clk.Advance(500 * time.Millisecond)
+	expectNext(t, gen, Window[int]{Start: epoch, End: epoch.Add(time.Second), Values: []int{1, 2}})
+
+	p.push(3)
+	<-clk.armed
+	finish(t, p, gen, Window[int]{Start: epoch.Add(time.Second), End: epoch.Add(2 * time.Second), Values: []int{3}})
+}
+
+func TestSlidingWindowsEventTime(t *testing.T) {
+	at := func(offset time.Duration) time.Time { return epoch.Add(offset) }
+	window := func(start, end time.Duration, values ...time.Duration) Window[time.Duration] {
+		return Window[time.Duration]{Start: at(start), End: at(end), Values: values}
+	}
+	ms := time.Millisecond
+
+	// The last value is late, its windows were already emitted.
+	upstream := fromSlice(500*ms, 1500*ms, 2500*ms, 200*ms)
+	expectValues(t, SlidingWindows(upstream, 2*time.Second, time.Second, at),
+		window(-1000*ms, 1000*ms, 500*ms),
+		window(0, 2000*ms, 500*ms, 1500*ms),
+		window(1000*ms, 3000*ms, 1500*ms, 2500*ms),
+		window(2000*ms, 4000*ms, 2500*ms),
+	)
+}
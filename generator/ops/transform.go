@@ -0,0 +1,114 @@
+package ops
+
+import (
+	"context"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Map creates (but does not start) a [types.Generator] emitting the values of the upstream generator transformed by
+// the mapper.
+// The upstream generator must not be started, it is started with the returned generator.
+//
+// If the mapper returns an error, the upstream generator is canceled and the error is returned from
+// [types.Generator.Wait].
+// If the mapper returns [safeconcurrencyerrors.Stop], the upstream generator is canceled and no error is returned.
+// The same applies to the callbacks of the other operators of this package.
+func Map[T any, U any](
+	upstream types.Generator[T],
+	mapper func(context.Context, T) (U, error),
+) types.Generator[U] {
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[U]) error {
+		mapped, err := mapper(ctx, value)
+		if err != nil {
+			return err
+		}
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, mapped)
+	}, nil)
+}
+
+// Filter creates (but does not start) a [types.Generator] emitting the values of the upstream generator for which the
+// predicate returns true.
+// See [Map] for details on the lifecycle of the upstream generator and error handling.
+func Filter[T any](
+	upstream types.Generator[T],
+	predicate func(context.Context, T) (bool, error),
+) types.Generator[T] {
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[T]) error {
+		keep, err := predicate(ctx, value)
+		if err != nil || !keep {
+			return err
+		}
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, value)
+	}, nil)
+}
+
+// FlatMap creates (but does not start) a [types.Generator] emitting the values of the generators returned by the
+// mapper for each value of the upstream generator, in order.
+// The generators returned by the mapper must not be started, they are started and consumed one at a time.
+// Their errors are returned from [types.Generator.Wait].
+// See [Map] for details on the lifecycle of the upstream generator and error handling.
+func FlatMap[T any, U any](
+	upstream types.Generator[T],
+	mapper func(context.Context, T) (types.Generator[U], error),
+) types.Generator[U] {
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[U]) error {
+		inner, err := mapper(ctx, value)
+		if err != nil {
+			return err
+		}
+
+		return consume(ctx, inner, emit[U], nil, emitter)
+	}, nil)
+}
+
+// Distinct creates (but does not start) a [types.Generator] emitting the values of the upstream generator which were
+// not emitted before.
+// The emitted values are remembered until the generator completes.
+// See [Map] for details on the lifecycle of the upstream generator.
+func Distinct[T comparable](upstream types.Generator[T]) types.Generator[T] {
+	seen := make(map[T]struct{})
+
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[T]) error {
+		if _, ok := seen[value]; ok {
+			return nil
+		}
+		seen[value] = struct{}{}
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, value)
+	}, nil)
+}
+
+// Scan creates (but does not start) a [types.Generator] emitting the successive values of an accumulator, starting
+// from the initial value and updated by the accumulate function for each value of the upstream generator.
+// The initial value is not emitted.
+// See [Map] for details on the lifecycle of the upstream generator and error handling.
+func Scan[T any, A any](
+	upstream types.Generator[T],
+	initial A,
+	accumulate func(context.Context, A, T) (A, error),
+) types.Generator[A] {
+	acc := initial
+
+	return newOperator(upstream, func(ctx context.Context, value T, emitter types.Emitter[A]) error {
+		next, err := accumulate(ctx, acc, value)
+		if err != nil {
+			return err
+		}
+		acc = next
+
+		//nolint:wrapcheck
+		return emitter.Emit(ctx, acc)
+	}, nil)
+}
+
+// emit is a step emitting the values unchanged.
+func emit[T any](ctx context.Context, value T, emitter types.Emitter[T]) error {
+	//nolint:wrapcheck
+	return emitter.Emit(ctx, value)
+}
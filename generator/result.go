@@ -0,0 +1,26 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/results"
+)
+
+// NewResults creates (but does not start) a [types.Generator] of [types.Result] with the specified results buffer
+// size, whose producer may fail single items with [types.ResultEmitter.EmitError].
+// See [github.com/Izzette/go-safeconcurrency/generator/ops.SkipErrors] and the neighbouring operators to handle the
+// errors of the items.
+func NewResults[T any](producer types.ResultProducer[T], buffer uint) types.Generator[types.Result[T]] {
+	return NewBuffered[types.Result[T]](&resultProducer[T]{producer: producer}, buffer)
+}
+
+// resultProducer implements [types.Producer] for a [types.ResultProducer].
+type resultProducer[T any] struct {
+	producer types.ResultProducer[T]
+}
+
+// Run implements [types.Producer.Run].
+func (p *resultProducer[T]) Run(ctx context.Context, emitter types.Emitter[types.Result[T]]) error {
+	return p.producer.Run(ctx, results.NewResultEmitter(emitter))
+}
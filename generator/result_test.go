@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+type testResultProducer struct {
+	itemErr error
+}
+
+func (p *testResultProducer) Run(ctx context.Context, h types.ResultEmitter[int]) error {
+	if err := h.EmitValue(ctx, 1); err != nil {
+		return err
+	}
+	if err := h.EmitError(ctx, p.itemErr); err != nil {
+		return err
+	}
+
+	return h.EmitValue(ctx, 3)
+}
+
+func TestResultsGenerator(t *testing.T) {
+	itemErr := errors.New("item error")
+	gen := NewResults[int](&testResultProducer{itemErr: itemErr}, 0)
+	gen.Start(context.Background())
+
+	var received []types.Result[int]
+	for result := range gen.Results() {
+		received = append(received, result)
+	}
+	if err := gen.Wait(); err != nil {
+		t.Errorf("unexpected error from Wait: %v", err)
+	}
+
+	if len(received) != 3 {
+		t.Fatalf("expected 3 results, got %v", received)
+	}
+	if received[0].Value != 1 || received[2].Value != 3 {
+		t.Errorf("expected values 1 and 3, got %v", received)
+	}
+	if !errors.Is(received[1].Err, itemErr) {
+		t.Errorf("expected the item error, got %v", received[1].Err)
+	}
+}
@@ -0,0 +1,150 @@
+package source
+
+import (
+	"context"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// FetchPage fetches the page at the cursor, returning its items and the cursor of the next page.
+// The zero value of the next cursor indicates the last page.
+type FetchPage[CursorT comparable, ItemT any] func(ctx context.Context, cursor CursorT) ([]ItemT, CursorT, error)
+
+// PaginatorConfig configures [Paginator].
+type PaginatorConfig[CursorT comparable] struct {
+	// Start is the cursor of the first page, for example the last cursor passed to Checkpoint to resume a previous run.
+	Start CursorT
+
+	// Prefetch fetches the next page while the items of the current page are emitted.
+	Prefetch bool
+
+	// Retry configures fetching the pages again when they fail, see [task.Retry].
+	// The pages are not retried if [task.RetryConfig.MaxAttempts] is lower than 2.
+	Retry task.RetryConfig
+
+	// Checkpoint is called with the cursor of the next page once all the items of the current page are emitted, it may
+	// be nil.
+	// It is not called after the last page.
+	// If it returns an error, the producer fails with the error.
+	//
+	// The items are emitted once they are in the results buffer of the generator, not once they are processed by the
+	// consumer: resuming from the checkpoint after a crash skips the items which were emitted but not yet processed,
+	// up to the size of the buffer plus one.
+	// The checkpoints are thus at-most-once with respect to the processing of the items, see
+	// [github.com/Izzette/go-safeconcurrency/generator/checkpoint] to checkpoint the items acknowledged by the consumer.
+	Checkpoint func(ctx context.Context, next CursorT) error
+}
+
+// Paginator creates a [types.Producer] emitting the items of the pages fetched from a paginated API.
+// The pages are fetched lazily, as the items of the previous page are received.
+// The producer fails if a page cannot be fetched, after retrying it as configured.
+func Paginator[CursorT comparable, ItemT any](
+	fetch FetchPage[CursorT, ItemT],
+	config PaginatorConfig[CursorT],
+) types.Producer[ItemT] {
+	return &paginator[CursorT, ItemT]{fetch: fetch, config: config}
+}
+
+// paginator implements [types.Producer] for [Paginator].
+type paginator[CursorT comparable, ItemT any] struct {
+	fetch  FetchPage[CursorT, ItemT]
+	config PaginatorConfig[CursorT]
+}
+
+// page is the result of fetching a page.
+type page[CursorT comparable, ItemT any] struct {
+	items []ItemT
+	next  CursorT
+}
+
+// Run implements [types.Producer.Run].
+func (p *paginator[CursorT, ItemT]) Run(ctx context.Context, emitter types.Emitter[ItemT]) error {
+	// The prefetched page is canceled once the producer returns.
+	fetchCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(context.Canceled)
+
+	var zero CursorT
+	cursor := p.config.Start
+	var prefetched <-chan pageResult[CursorT, ItemT]
+	if p.config.Prefetch {
+		prefetched = p.fetchAsync(fetchCtx, cursor)
+	}
+	for {
+		var result pageResult[CursorT, ItemT]
+		if prefetched != nil {
+			result = <-prefetched
+		} else {
+			result.page, result.err = p.fetchPage(fetchCtx, cursor)
+		}
+		if result.err != nil {
+			return result.err
+		}
+
+		last := result.page.next == zero
+		if p.config.Prefetch && !last {
+			prefetched = p.fetchAsync(fetchCtx, result.page.next)
+		}
+		for _, item := range result.page.items {
+			if err := emitter.Emit(ctx, item); err != nil {
+				//nolint:wrapcheck
+				return err
+			}
+		}
+		if last {
+			return nil
+		}
+
+		if p.config.Checkpoint != nil {
+			if err := p.config.Checkpoint(ctx, result.page.next); err != nil {
+				return err
+			}
+		}
+		cursor = result.page.next
+	}
+}
+
+// pageResult is sent by [paginator.fetchAsync].
+type pageResult[CursorT comparable, ItemT any] struct {
+	page page[CursorT, ItemT]
+	err  error
+}
+
+// fetchAsync fetches the page at the cursor in a new goroutine.
+func (p *paginator[CursorT, ItemT]) fetchAsync(
+	ctx context.Context,
+	cursor CursorT,
+) <-chan pageResult[CursorT, ItemT] {
+	// The channel is buffered, so that the goroutine does not block if the result is never received.
+	results := make(chan pageResult[CursorT, ItemT], 1)
+	go func() {
+		pg, err := p.fetchPage(ctx, cursor)
+		results <- pageResult[CursorT, ItemT]{page: pg, err: err}
+	}()
+
+	return results
+}
+
+// fetchPage fetches the page at the cursor, retrying it as configured.
+func (p *paginator[CursorT, ItemT]) fetchPage(ctx context.Context, cursor CursorT) (page[CursorT, ItemT], error) {
+	fetch := &fetchTask[CursorT, ItemT]{fetch: p.fetch, cursor: cursor}
+	if p.config.Retry.MaxAttempts < 2 {
+		return fetch.Execute(ctx, struct{}{})
+	}
+
+	//nolint:wrapcheck
+	return task.Retry[struct{}, page[CursorT, ItemT]](fetch, p.config.Retry).Execute(ctx, struct{}{})
+}
+
+// fetchTask implements [types.Task] to fetch a page with [task.Retry].
+type fetchTask[CursorT comparable, ItemT any] struct {
+	fetch  FetchPage[CursorT, ItemT]
+	cursor CursorT
+}
+
+// Execute implements [types.Task.Execute].
+func (t *fetchTask[CursorT, ItemT]) Execute(ctx context.Context, _ struct{}) (page[CursorT, ItemT], error) {
+	items, next, err := t.fetch(ctx, t.cursor)
+
+	return page[CursorT, ItemT]{items: items, next: next}, err
+}
@@ -0,0 +1,140 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/generator"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+//nolint:gochecknoglobals
+var testPages = map[string]struct {
+	items []int
+	next  string
+}{
+	"":   {items: []int{1, 2}, next: "p2"},
+	"p2": {items: []int{3}, next: "p3"},
+	"p3": {items: []int{4, 5}, next: ""},
+}
+
+func fetchTestPage(_ context.Context, cursor string) ([]int, string, error) {
+	pg := testPages[cursor]
+
+	return pg.items, pg.next, nil
+}
+
+func TestPaginator(t *testing.T) {
+	var checkpoints []string
+	values := collect(t, Paginator(fetchTestPage, PaginatorConfig[string]{
+		Checkpoint: func(_ context.Context, next string) error {
+			checkpoints = append(checkpoints, next)
+
+			return nil
+		},
+	}))
+	if expected := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	if expected := []string{"p2", "p3"}; !reflect.DeepEqual(checkpoints, expected) {
+		t.Errorf("Expected checkpoints %v, got %v", expected, checkpoints)
+	}
+
+	// Resuming from a checkpoint only emits the following pages.
+	values = collect(t, Paginator(fetchTestPage, PaginatorConfig[string]{Start: "p3"}))
+	if expected := []int{4, 5}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
+
+func TestPaginatorPrefetch(t *testing.T) {
+	fetched := make(chan string, len(testPages))
+	gen := generator.New(Paginator(func(ctx context.Context, cursor string) ([]int, string, error) {
+		fetched <- cursor
+
+		return fetchTestPage(ctx, cursor)
+	}, PaginatorConfig[string]{Prefetch: true}))
+	gen.Start(context.Background())
+
+	if value := <-gen.Results(); value != 1 {
+		t.Errorf("Expected 1, got %d", value)
+	}
+	// The second page is fetched while the first page is still being received.
+	for _, expected := range []string{"", "p2"} {
+		if cursor := <-fetched; cursor != expected {
+			t.Errorf("Expected a fetch of %q, got %q", expected, cursor)
+		}
+	}
+
+	values := make([]int, 0)
+	for value := range gen.Results() {
+		values = append(values, value)
+	}
+	if err := gen.Wait(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []int{2, 3, 4, 5}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
+
+func TestPaginatorRetry(t *testing.T) {
+	errTransient := errors.New("transient error")
+	clk := clock.NewFake(time.Now())
+	failures := map[string]int{"p2": 1, "p3": 2}
+	gen := generator.New(Paginator(func(ctx context.Context, cursor string) ([]int, string, error) {
+		if failures[cursor] > 0 {
+			failures[cursor]--
+
+			return nil, "", errTransient
+		}
+
+		return fetchTestPage(ctx, cursor)
+	}, PaginatorConfig[string]{Retry: task.RetryConfig{
+		MaxAttempts: 2,
+		Backoff:     func(uint) time.Duration { return time.Second },
+		Clock:       clk,
+	}}))
+	gen.Start(context.Background())
+
+	for _, expected := range []int{1, 2} {
+		if value := <-gen.Results(); value != expected {
+			t.Errorf("Expected %d, got %d", expected, value)
+		}
+	}
+	// The second page is fetched again after the backoff.
+	clk.WaitForTimers(1)
+	clk.Advance(time.Second)
+	if value := <-gen.Results(); value != 3 {
+		t.Errorf("Expected 3, got %d", value)
+	}
+
+	// The third page fails once more than the maximum attempts.
+	clk.WaitForTimers(1)
+	clk.Advance(time.Second)
+	var retryErr *task.RetryError
+	if err := generator.Drain(gen); !errors.As(err, &retryErr) || !errors.Is(err, errTransient) {
+		t.Fatalf("Expected a RetryError, got %v", err)
+	}
+	if retryErr.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", retryErr.Attempts)
+	}
+}
+
+func TestPaginatorCheckpointError(t *testing.T) {
+	checkpointErr := errors.New("checkpoint error")
+	gen := generator.New(Paginator(fetchTestPage, PaginatorConfig[string]{
+		Checkpoint: func(context.Context, string) error { return checkpointErr },
+	}))
+	values, err := generator.Collect(context.Background(), gen)
+	if !errors.Is(err, checkpointErr) {
+		t.Errorf("Expected the checkpoint error, got %v", err)
+	}
+	if expected := []int{1, 2}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
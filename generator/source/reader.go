@@ -0,0 +1,258 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Lines creates a [types.Producer] emitting the lines read from the reader, without their line endings.
+// Both "\n" and "\r\n" line endings are removed, and the last line is emitted even if it has no line ending.
+// Reading from the reader cannot be interrupted, the producer only returns once the current read completes.
+func Lines(r io.Reader) types.Producer[string] {
+	return &delimitedProducer{r: r, delim: '\n', trimCR: true}
+}
+
+// Delimited creates a [types.Producer] emitting the records read from the reader separated by the delimiter, without
+// the delimiter.
+// The last record is emitted even if it is not followed by the delimiter.
+// See [Lines] for details on reading.
+func Delimited(r io.Reader, delim byte) types.Producer[string] {
+	return &delimitedProducer{r: r, delim: delim}
+}
+
+// delimitedProducer implements [types.Producer] for [Lines] and [Delimited].
+type delimitedProducer struct {
+	r      io.Reader
+	delim  byte
+	trimCR bool
+}
+
+// Run implements [types.Producer.Run].
+func (p *delimitedProducer) Run(ctx context.Context, emitter types.Emitter[string]) error {
+	br := bufio.NewReader(p.r)
+	for {
+		record, err := br.ReadString(p.delim)
+		if err != nil && !errors.Is(err, io.EOF) {
+			//nolint:wrapcheck
+			return err
+		}
+		if errors.Is(err, io.EOF) && record == "" {
+			return nil
+		}
+
+		record = strings.TrimSuffix(record, string(p.delim))
+		if p.trimCR {
+			record = strings.TrimSuffix(record, "\r")
+		}
+		if emitErr := emitter.Emit(ctx, record); emitErr != nil {
+			//nolint:wrapcheck
+			return emitErr
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// NDJSON creates a [types.Producer] emitting the values decoded from the newline-delimited JSON read from the reader.
+// The producer fails on the first value which cannot be decoded.
+// See [Lines] for details on reading.
+func NDJSON[T any](r io.Reader) types.Producer[T] {
+	return &ndjsonProducer[T]{r: r}
+}
+
+// ndjsonProducer implements [types.Producer] for [NDJSON].
+type ndjsonProducer[T any] struct {
+	r io.Reader
+}
+
+// Run implements [types.Producer.Run].
+func (p *ndjsonProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	decoder := json.NewDecoder(p.r)
+	for {
+		var value T
+		if err := decoder.Decode(&value); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("decoding JSON value at offset %d: %w", decoder.InputOffset(), err)
+		}
+		if err := emitter.Emit(ctx, value); err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+	}
+}
+
+// CSV creates a [types.Producer] emitting the structs decoded from the records read from the [csv.Reader].
+// The first record is the header, whose columns are matched to the fields of the struct by their "csv" tag, or by
+// their name if they have no tag.
+// Fields tagged with "-" and columns without a matching field are ignored.
+// The embedded struct pointers which fields are promoted through are allocated as needed, the fields promoted through
+// unexported embedded pointers are ignored.
+// The fields may be strings, booleans, integers, floats, or implement [encoding.TextUnmarshaler].
+// The producer fails on the first record which cannot be decoded.
+// See [Lines] for details on reading.
+func CSV[T any](r *csv.Reader) types.Producer[T] {
+	return &csvProducer[T]{r: r}
+}
+
+// csvProducer implements [types.Producer] for [CSV].
+type csvProducer[T any] struct {
+	r *csv.Reader
+}
+
+// Run implements [types.Producer.Run].
+func (p *csvProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("decoding CSV into %v: not a struct", structType)
+	}
+
+	header, err := p.r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+	fields := csvFields(structType, header)
+
+	for {
+		record, err := p.r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("reading CSV record: %w", err)
+		}
+
+		var value T
+		target := reflect.ValueOf(&value).Elem()
+		for column, field := range fields {
+			if field == nil || column >= len(record) {
+				continue
+			}
+			if err := setField(fieldByIndex(target, field), record[column]); err != nil {
+				line, _ := p.r.FieldPos(column)
+
+				return fmt.Errorf("decoding CSV column %q on line %d: %w", header[column], line, err)
+			}
+		}
+		if err := emitter.Emit(ctx, value); err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+	}
+}
+
+// csvFields returns the index of the field of the struct type for each column of the header, or nil if the column has
+// no matching field.
+func csvFields(structType reflect.Type, header []string) [][]int {
+	byName := make(map[string][]int)
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous || !settable(structType, field.Index) {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		byName[name] = field.Index
+	}
+
+	fields := make([][]int, len(header))
+	for column, name := range header {
+		fields[column] = byName[name]
+	}
+
+	return fields
+}
+
+// settable returns false if the field is promoted through an unexported embedded pointer, which cannot be allocated.
+func settable(structType reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		field := structType.Field(i)
+		structType = field.Type
+		if structType.Kind() == reflect.Pointer {
+			if !field.IsExported() {
+				return false
+			}
+			structType = structType.Elem()
+		}
+	}
+
+	return true
+}
+
+// fieldByIndex returns the nested field of the struct, allocating the nil embedded pointers it is promoted through.
+func fieldByIndex(value reflect.Value, index []int) reflect.Value {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(fieldIndex)
+	}
+
+	return value
+}
+
+// setField parses the text into the field.
+func setField(field reflect.Value, text string) error {
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		//nolint:wrapcheck
+		return unmarshaler.UnmarshalText([]byte(text))
+	}
+
+	//nolint:exhaustive
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %v", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package source
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+func TestLines(t *testing.T) {
+	values := collect(t, Lines(strings.NewReader("a\r\nb\n\nc")))
+	if !reflect.DeepEqual(values, []string{"a", "b", "", "c"}) {
+		t.Errorf("Expected [a b  c], got %q", values)
+	}
+
+	if values := collect(t, Lines(strings.NewReader(""))); len(values) != 0 {
+		t.Errorf("Expected no lines, got %q", values)
+	}
+}
+
+func TestDelimited(t *testing.T) {
+	values := collect(t, Delimited(strings.NewReader("a\x00b\x00"), 0))
+	if !reflect.DeepEqual(values, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %q", values)
+	}
+}
+
+type record struct {
+	Name  string `csv:"name" json:"name"`
+	Count int    `csv:"count" json:"count"`
+	Level level  `csv:"level" json:"-"`
+	Note  string `csv:"-" json:"-"`
+}
+
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	parsed, err := strconv.Atoi(strings.TrimPrefix(string(text), "L"))
+	*l = level(parsed)
+
+	return err
+}
+
+func TestNDJSON(t *testing.T) {
+	values := collect(t, NDJSON[record](strings.NewReader(`{"name":"a","count":1}`+"\n"+`{"name":"b","count":2}`+"\n")))
+	expected := []record{{Name: "a", Count: 1}, {Name: "b", Count: 2}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+
+	_, err := generator.Collect(context.Background(), generator.New(NDJSON[record](strings.NewReader(`{"name":`))))
+	if err == nil {
+		t.Error("Expected a decoding error")
+	}
+}
+
+type Embedded struct {
+	Name string
+}
+
+type hidden struct {
+	Secret string
+}
+
+type embeddingRecord struct {
+	ID string
+	*Embedded
+	*hidden
+}
+
+func TestCSVEmbeddedPointers(t *testing.T) {
+	// The exported embedded pointer is allocated, the fields promoted through the unexported one are ignored.
+	input := "ID,Name,Secret\n1,a,s\n"
+	values := collect(t, CSV[embeddingRecord](csv.NewReader(strings.NewReader(input))))
+	if len(values) != 1 || values[0].ID != "1" || values[0].Embedded == nil || values[0].Name != "a" ||
+		values[0].hidden != nil {
+		t.Errorf("Expected ID 1 and name a, got %+v", values)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	input := "count,name,unknown,level,Note\n1,a,x,L3,ignored\n2,b,y,L4,ignored\n"
+	values := collect(t, CSV[record](csv.NewReader(strings.NewReader(input))))
+	expected := []record{{Name: "a", Count: 1, Level: 3}, {Name: "b", Count: 2, Level: 4}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+
+	input = "name,count\na,1\nb,two\n"
+	gen := generator.New(CSV[record](csv.NewReader(strings.NewReader(input))))
+	values, err := generator.Collect(context.Background(), gen)
+	if !errors.Is(err, strconv.ErrSyntax) || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected a syntax error on line 3, got %v", err)
+	}
+	if len(values) != 1 {
+		t.Errorf("Expected 1 value before the error, got %v", values)
+	}
+}
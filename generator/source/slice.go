@@ -0,0 +1,149 @@
+package source
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Slice creates a [types.Producer] emitting the values of the slice in order.
+func Slice[T any](values []T) types.Producer[T] {
+	return &sliceProducer[T]{values: values}
+}
+
+// sliceProducer implements [types.Producer] for [Slice].
+type sliceProducer[T any] struct {
+	values []T
+}
+
+// Run implements [types.Producer.Run].
+func (p *sliceProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	for _, value := range p.values {
+		if err := emitter.Emit(ctx, value); err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Entry is a key-value pair of a map emitted by [Map] and [SortedMap].
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Map creates a [types.Producer] emitting the entries of the map, in the unspecified iteration order of the map.
+// The map must not be modified while the producer is running.
+func Map[K comparable, V any](m map[K]V) types.Producer[Entry[K, V]] {
+	return SortedMap(m, nil)
+}
+
+// SortedMap creates a [types.Producer] emitting the entries of the map, in the order of the keys given by less.
+// If less is nil, the entries are emitted in the unspecified iteration order of the map.
+// The map must not be modified while the producer is running.
+func SortedMap[K comparable, V any](m map[K]V, less func(K, K) bool) types.Producer[Entry[K, V]] {
+	return &mapProducer[K, V]{m: m, less: less}
+}
+
+// mapProducer implements [types.Producer] for [Map] and [SortedMap].
+type mapProducer[K comparable, V any] struct {
+	m    map[K]V
+	less func(K, K) bool
+}
+
+// Run implements [types.Producer.Run].
+func (p *mapProducer[K, V]) Run(ctx context.Context, emitter types.Emitter[Entry[K, V]]) error {
+	if p.less == nil {
+		for key, value := range p.m {
+			if err := emitter.Emit(ctx, Entry[K, V]{Key: key, Value: value}); err != nil {
+				//nolint:wrapcheck
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	keys := make([]K, 0, len(p.m))
+	for key := range p.m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return p.less(keys[i], keys[j]) })
+	for _, key := range keys {
+		if err := emitter.Emit(ctx, Entry[K, V]{Key: key, Value: p.m[key]}); err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Channel creates a [types.Producer] emitting the values received from the channel, until it is closed.
+// The producer returns as soon as the context is canceled, even if it is waiting for a value.
+func Channel[T any](ch <-chan T) types.Producer[T] {
+	return &channelProducer[T]{ch: ch}
+}
+
+// channelProducer implements [types.Producer] for [Channel].
+type channelProducer[T any] struct {
+	ch <-chan T
+}
+
+// Run implements [types.Producer.Run].
+func (p *channelProducer[T]) Run(ctx context.Context, emitter types.Emitter[T]) error {
+	for {
+		select {
+		case <-ctx.Done():
+			//nolint:wrapcheck
+			return context.Cause(ctx)
+		case value, ok := <-p.ch:
+			if !ok {
+				return nil
+			}
+			if err := emitter.Emit(ctx, value); err != nil {
+				//nolint:wrapcheck
+				return err
+			}
+		}
+	}
+}
+
+// Range creates a [types.Producer] emitting the integers from start up to, but not including, endExclusive.
+func Range(start int, endExclusive int) types.Producer[int] {
+	return RangeStep(start, endExclusive, 1)
+}
+
+// RangeStep creates a [types.Producer] emitting the integers from start up to, but not including, endExclusive,
+// incrementing by step.
+// A negative step counts down from start to endExclusive.
+// It panics if step is 0.
+func RangeStep(start int, endExclusive int, step int) types.Producer[int] {
+	if step == 0 {
+		panic("range step must not be 0")
+	}
+
+	return &rangeProducer{start: start, endExclusive: endExclusive, step: step}
+}
+
+// rangeProducer implements [types.Producer] for [Range] and [RangeStep].
+type rangeProducer struct {
+	start        int
+	endExclusive int
+	step         int
+}
+
+// Run implements [types.Producer.Run].
+func (p *rangeProducer) Run(ctx context.Context, emitter types.Emitter[int]) error {
+	for i := p.start; (p.step > 0 && i < p.endExclusive) || (p.step < 0 && i > p.endExclusive); i += p.step {
+		if err := emitter.Emit(ctx, i); err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/generator"
+)
+
+func collect[T any](t *testing.T, producer types.Producer[T]) []T {
+	t.Helper()
+
+	values, err := generator.Collect(context.Background(), generator.New(producer))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return values
+}
+
+func TestSlice(t *testing.T) {
+	if values := collect(t, Slice([]string{"a", "b"})); !reflect.DeepEqual(values, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", values)
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+	expected := []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}
+
+	entries := collect(t, Map(m))
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %v, got %v", expected, entries)
+	}
+
+	less := func(a, b string) bool { return a < b }
+	if entries := collect(t, SortedMap(m, less)); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %v, got %v", expected, entries)
+	}
+}
+
+func TestChannel(t *testing.T) {
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	if values := collect(t, Channel(ch)); !reflect.DeepEqual(values, []int{1, 2}) {
+		t.Errorf("Expected [1 2], got %v", values)
+	}
+
+	// The producer returns once canceled, even though the channel is never closed.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := generator.New(Channel(make(chan int))).Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRange(t *testing.T) {
+	if values := collect(t, Range(2, 5)); !reflect.DeepEqual(values, []int{2, 3, 4}) {
+		t.Errorf("Expected [2 3 4], got %v", values)
+	}
+	if values := collect(t, RangeStep(5, 0, -2)); !reflect.DeepEqual(values, []int{5, 3, 1}) {
+		t.Errorf("Expected [5 3 1], got %v", values)
+	}
+	if values := collect(t, Range(5, 2)); len(values) != 0 {
+		t.Errorf("Expected no values, got %v", values)
+	}
+}
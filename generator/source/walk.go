@@ -0,0 +1,118 @@
+package source
+
+import (
+	"context"
+	"io/fs"
+	"path"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// WalkEntry is a file or directory emitted by [Walk].
+type WalkEntry struct {
+	// Path is the path of the entry in the file system, as with [fs.WalkDir].
+	Path string
+	fs.DirEntry
+}
+
+// WalkOption configures [Walk].
+type WalkOption func(*walkConfig)
+
+// walkConfig is configured by [WalkOption].
+type walkConfig struct {
+	include []string
+	exclude []string
+	dirs    bool
+}
+
+// WithInclude only emits the files whose base name matches any of the patterns, see [path.Match].
+// The directories are still walked.
+func WithInclude(patterns ...string) WalkOption {
+	return func(c *walkConfig) {
+		c.include = append(c.include, patterns...)
+	}
+}
+
+// WithExclude skips the files and directories whose base name matches any of the patterns, see [path.Match].
+// The contents of the excluded directories are skipped.
+func WithExclude(patterns ...string) WalkOption {
+	return func(c *walkConfig) {
+		c.exclude = append(c.exclude, patterns...)
+	}
+}
+
+// WithDirectories also emits the directories, which are not emitted by default.
+// The directories are emitted before their contents, and are not subject to [WithInclude].
+func WithDirectories() WalkOption {
+	return func(c *walkConfig) {
+		c.dirs = true
+	}
+}
+
+// Walk creates a [types.Producer] emitting the entries of the file tree rooted at root, recursively in lexical order.
+// Use [os.DirFS] to walk a directory of the operating system.
+// The producer fails on the first error reading a directory, or if a pattern is malformed.
+func Walk(fsys fs.FS, root string, opts ...WalkOption) types.Producer[WalkEntry] {
+	var config walkConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &walkProducer{fsys: fsys, root: root, config: config}
+}
+
+// walkProducer implements [types.Producer] for [Walk].
+type walkProducer struct {
+	fsys   fs.FS
+	root   string
+	config walkConfig
+}
+
+// Run implements [types.Producer.Run].
+func (p *walkProducer) Run(ctx context.Context, emitter types.Emitter[WalkEntry]) error {
+	//nolint:wrapcheck
+	return fs.WalkDir(p.fsys, p.root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		base := path.Base(name)
+		excluded, err := matchAny(p.config.exclude, base)
+		if err != nil {
+			return err
+		}
+		if excluded && name != p.root {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.IsDir() {
+			if !p.config.dirs {
+				return nil
+			}
+		} else if len(p.config.include) > 0 {
+			included, err := matchAny(p.config.include, base)
+			if err != nil || !included {
+				return err
+			}
+		}
+
+		return emitter.Emit(ctx, WalkEntry{Path: name, DirEntry: entry})
+	})
+}
+
+// matchAny reports whether the name matches any of the patterns.
+func matchAny(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, name)
+		if err != nil || matched {
+			//nolint:wrapcheck
+			return matched, err
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,50 @@
+package source
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func walkPaths(t *testing.T, opts ...WalkOption) []string {
+	t.Helper()
+
+	fsys := fstest.MapFS{
+		"a.go":            {},
+		"b.txt":           {},
+		"sub/c.go":        {},
+		"sub/deep/d.go":   {},
+		".git/config":     {},
+		"vendor/x/e.go":   {},
+		"vendor/x/f.txt":  {},
+		"sub/deep/g.json": {},
+	}
+	entries := collect(t, Walk(fsys, ".", opts...))
+	paths := make([]string, len(entries))
+	for i, entry := range entries {
+		paths[i] = entry.Path
+	}
+
+	return paths
+}
+
+func TestWalk(t *testing.T) {
+	expected := []string{".git/config", "a.go", "b.txt", "sub/c.go", "sub/deep/d.go", "sub/deep/g.json", "vendor/x/e.go",
+		"vendor/x/f.txt"}
+	if paths := walkPaths(t); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
+
+func TestWalkFilters(t *testing.T) {
+	expected := []string{"a.go", "sub/c.go", "sub/deep/d.go"}
+	if paths := walkPaths(t, WithInclude("*.go"), WithExclude(".*", "vendor")); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	expected = []string{".", "a.go", "sub", "sub/c.go", "sub/deep", "sub/deep/d.go"}
+	paths := walkPaths(t, WithInclude("*.go"), WithExclude(".git", "vendor"), WithDirectories())
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
@@ -0,0 +1,38 @@
+package safeconcurrencycontext
+
+import (
+	"context"
+	"time"
+)
+
+// WithoutCancel returns a [context.Context] which carries the values of its parent but is never canceled, and has no
+// deadline.
+func WithoutCancel(parent context.Context) context.Context {
+	return withoutCancel{parent}
+}
+
+// withoutCancel is a [context.Context] which carries the values of its parent but is never canceled.
+type withoutCancel struct {
+	//nolint:containedctx
+	parent context.Context
+}
+
+// Deadline implements [context.Context.Deadline].
+func (withoutCancel) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done implements [context.Context.Done].
+func (withoutCancel) Done() <-chan struct{} {
+	return nil
+}
+
+// Err implements [context.Context.Err].
+func (withoutCancel) Err() error {
+	return nil
+}
+
+// Value implements [context.Context.Value].
+func (c withoutCancel) Value(key any) any {
+	return c.parent.Value(key)
+}
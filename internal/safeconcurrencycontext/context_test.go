@@ -0,0 +1,27 @@
+package safeconcurrencycontext
+
+import (
+	"context"
+	"testing"
+)
+
+type testKey struct{}
+
+func TestWithoutCancel(t *testing.T) {
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), testKey{}, "value"))
+	cancel()
+
+	ctx := WithoutCancel(parent)
+	if err := context.Cause(ctx); err != nil {
+		t.Errorf("Expected nil cause, got %v", err)
+	}
+	if ctx.Done() != nil {
+		t.Error("Expected nil done channel")
+	}
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected no deadline")
+	}
+	if value := ctx.Value(testKey{}); value != "value" {
+		t.Errorf("Expected value to be carried, got %v", value)
+	}
+}
@@ -0,0 +1,120 @@
+package safeconcurrencysync
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+)
+
+// PauseGate blocks goroutines from entering a section while it is paused, and keeps track of the goroutines inside the
+// section so that pausing can wait for them to leave.
+// The zero value is an open gate.
+type PauseGate struct {
+	mu     sync.Mutex
+	active uint
+	// pause is nil while the gate is open.
+	pause *pauseEpoch
+	// pausing is closed when the gate is paused, it is created by the first call to Enter while the gate is open.
+	pausing chan struct{}
+}
+
+// pauseEpoch represents a single period during which the [PauseGate] is paused.
+type pauseEpoch struct {
+	// resumed is closed when the gate is resumed.
+	resumed chan struct{}
+	// drained is closed when no goroutine remains inside the section.
+	drained chan struct{}
+}
+
+// Enter blocks while the [PauseGate] is paused, and then records that the calling goroutine is inside the section.
+// Each call to Enter must be followed by a call to [PauseGate.Leave].
+// The returned channel is closed once the gate is paused, allowing a goroutine waiting for work inside the section to
+// leave it instead of delaying the pause.
+func (g *PauseGate) Enter() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for g.pause != nil {
+		resumed := g.pause.resumed
+		g.mu.Unlock()
+		<-resumed
+		g.mu.Lock()
+	}
+	g.active++
+	if g.pausing == nil {
+		g.pausing = make(chan struct{})
+	}
+
+	return g.pausing
+}
+
+// Leave records that the calling goroutine has left the section.
+func (g *PauseGate) Leave() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.active--
+	// No goroutine may enter while paused, so the drained channel is closed at most once.
+	if g.active == 0 && g.pause != nil {
+		close(g.pause.drained)
+	}
+}
+
+// Pause closes the [PauseGate], and then waits for all goroutines inside the section to leave.
+// If the [context.Context] is canceled before the section is drained, the [context.Cause] is returned, the gate
+// remains paused nonetheless.
+// If the gate is resumed before the section is drained, [safeconcurrencyerrors.ErrPoolResumed] is returned.
+// It is safe to call Pause when the gate is already paused.
+func (g *PauseGate) Pause(ctx context.Context) error {
+	g.mu.Lock()
+	if g.pause == nil {
+		g.pause = &pauseEpoch{resumed: make(chan struct{}), drained: make(chan struct{})}
+		if g.pausing != nil {
+			close(g.pausing)
+			g.pausing = nil
+		}
+		if g.active == 0 {
+			close(g.pause.drained)
+		}
+	}
+	pause := g.pause
+	g.mu.Unlock()
+
+	// select is not deterministic, prefer reporting success if the section is already drained.
+	select {
+	case <-pause.drained:
+		return nil
+	default:
+	}
+
+	select {
+	case <-pause.drained:
+		return nil
+	case <-pause.resumed:
+		return safeconcurrencyerrors.ErrPoolResumed
+	case <-ctx.Done():
+		//nolint:wrapcheck
+		return context.Cause(ctx)
+	}
+}
+
+// Resume opens the [PauseGate], allowing the goroutines blocked in [PauseGate.Enter] to proceed.
+// It is safe to call Resume when the gate is not paused.
+func (g *PauseGate) Resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.pause != nil {
+		close(g.pause.resumed)
+		g.pause = nil
+	}
+}
+
+// Paused returns true if the [PauseGate] is paused.
+func (g *PauseGate) Paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.pause != nil
+}
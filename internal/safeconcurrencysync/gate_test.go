@@ -0,0 +1,113 @@
+package safeconcurrencysync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+)
+
+func TestPauseGateIdle(t *testing.T) {
+	gate := &PauseGate{}
+	if gate.Paused() {
+		t.Error("Expected gate to be open")
+	}
+
+	// Pausing an idle gate returns immediately.
+	if err := gate.Pause(context.Background()); err != nil {
+		t.Errorf("Expected nil error, but got %v", err)
+	}
+	if !gate.Paused() {
+		t.Error("Expected gate to be paused")
+	}
+
+	gate.Resume()
+	if gate.Paused() {
+		t.Error("Expected gate to be open")
+	}
+	// Resume is idempotent.
+	gate.Resume()
+}
+
+func TestPauseGateBlocksEnter(t *testing.T) {
+	gate := &PauseGate{}
+	if err := gate.Pause(context.Background()); err != nil {
+		t.Fatalf("Expected nil error, but got %v", err)
+	}
+
+	entered := make(chan struct{})
+	go func() {
+		defer close(entered)
+		gate.Enter()
+		gate.Leave()
+	}()
+
+	select {
+	case <-entered:
+		t.Fatal("Expected Enter to block while paused")
+	default:
+	}
+
+	gate.Resume()
+	<-entered
+}
+
+func TestPauseGateWaitsForDrain(t *testing.T) {
+	gate := &PauseGate{}
+	gate.Enter()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := gate.Pause(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, but got %v", err)
+	}
+	if !gate.Paused() {
+		t.Error("Expected gate to remain paused")
+	}
+
+	paused := make(chan error)
+	go func() { paused <- gate.Pause(context.Background()) }()
+	gate.Leave()
+	if err := <-paused; err != nil {
+		t.Errorf("Expected nil error, but got %v", err)
+	}
+}
+
+func TestPauseGateSignalsPausing(t *testing.T) {
+	gate := &PauseGate{}
+	pausing := gate.Enter()
+
+	paused := make(chan error)
+	go func() { paused <- gate.Pause(context.Background()) }()
+	// The goroutine inside the section leaves once it is signaled, allowing the pause to complete.
+	<-pausing
+	gate.Leave()
+	if err := <-paused; err != nil {
+		t.Errorf("Expected nil error, but got %v", err)
+	}
+
+	gate.Resume()
+	select {
+	case <-gate.Enter():
+		t.Error("Expected a new pausing channel once resumed")
+	default:
+	}
+	gate.Leave()
+}
+
+func TestPauseGateResumedBeforeDrain(t *testing.T) {
+	gate := &PauseGate{}
+	gate.Enter()
+	defer gate.Leave()
+
+	paused := make(chan error)
+	go func() { paused <- gate.Pause(context.Background()) }()
+	for !gate.Paused() {
+		// Wait for the goroutine to pause the gate.
+	}
+	gate.Resume()
+	if err := <-paused; !errors.Is(err, safeconcurrencyerrors.ErrPoolResumed) {
+		t.Errorf("Expected ErrPoolResumed, but got %v", err)
+	}
+}
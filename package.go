@@ -4,7 +4,20 @@
 // For main functionality, see:
 //   - For types and interfaces: [github.com/Izzette/go-safeconcurrency/api/types]
 //   - For creating generators: [github.com/Izzette/go-safeconcurrency/generator]
+//   - For transforming generators: [github.com/Izzette/go-safeconcurrency/generator/ops]
+//   - For producing common inputs: [github.com/Izzette/go-safeconcurrency/generator/source]
+//   - For resuming generators from checkpoints: [github.com/Izzette/go-safeconcurrency/generator/checkpoint]
 //   - For creating worker pools and tasks: [github.com/Izzette/go-safeconcurrency/workpool]
+//   - For scheduling delayed tasks: [github.com/Izzette/go-safeconcurrency/workpool/scheduler]
+//   - For periodic jobs: [github.com/Izzette/go-safeconcurrency/workpool/cron]
+//   - For limiting the concurrency of HTTP clients: [github.com/Izzette/go-safeconcurrency/workpool/httptransport]
+//   - For running external programs: [github.com/Izzette/go-safeconcurrency/workpool/command]
+//   - For recording and re-driving failed tasks: [github.com/Izzette/go-safeconcurrency/workpool/deadletter]
+//   - For durable task queues: [github.com/Izzette/go-safeconcurrency/workpool/diskqueue]
+//   - For work queues with visibility timeouts: [github.com/Izzette/go-safeconcurrency/workpool/leasequeue]
+//   - For process-isolated worker pools: [github.com/Izzette/go-safeconcurrency/workpool/procpool]
+//   - For serializable task registries: [github.com/Izzette/go-safeconcurrency/workpool/registry]
+//   - For injectable clocks: [github.com/Izzette/go-safeconcurrency/clock]
 //   - For creating event loops: [github.com/Izzette/go-safeconcurrency/eventloop]
 //   - For examples: [github.com/Izzette/go-safeconcurrency/examples]
 package safeconcurrency
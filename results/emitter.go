@@ -9,6 +9,8 @@ import (
 
 // NewEmitter creates a new [types.Emitter] and is used to emit results from a [types.Producer] or
 // [types.StreamingTask].
+// Emit blocks until the result is received, see [NewDropNewestEmitter] and the neighbouring constructors to drop
+// results instead.
 func NewEmitter[T any](results chan<- T) types.Emitter[T] {
 	return &emitter[T]{results: results, closeOnce: &sync.Once{}}
 }
@@ -0,0 +1,256 @@
+package results
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// NewDropNewestEmitter creates a [types.LossyEmitter] wrapping the emitter passed to a [types.Producer] or
+// [types.StreamingTask], which buffers up to size values and drops the newest values once the buffer is full.
+// See [NewRingEmitter] for details on the lifecycle of the lossy emitters.
+func NewDropNewestEmitter[T any](
+	ctx context.Context,
+	downstream types.Emitter[T],
+	size uint,
+) types.LossyEmitter[T] {
+	return newLossyEmitter[T](ctx, downstream, newRing[T](size, false))
+}
+
+// NewRingEmitter creates a [types.LossyEmitter] wrapping the emitter passed to a [types.Producer] or
+// [types.StreamingTask], which buffers up to size values and overwrites the oldest values once the buffer is full.
+//
+// The buffered values are forwarded to the downstream emitter from a goroutine, until the context is canceled.
+// Emit never blocks, but returns an error once the context is canceled.
+// Close must be called before the producer or task returns: it waits for the buffered values to be forwarded, but does
+// not close the downstream emitter, which is closed by the generator or the worker pool.
+func NewRingEmitter[T any](ctx context.Context, downstream types.Emitter[T], size uint) types.LossyEmitter[T] {
+	return newLossyEmitter[T](ctx, downstream, newRing[T](size, true))
+}
+
+// NewCoalescingEmitter creates a [types.LossyEmitter] wrapping the emitter passed to a [types.Producer] or
+// [types.StreamingTask], which only buffers the latest value for each key.
+// A value replacing a buffered value of the same key keeps its position, and the replaced value is dropped.
+// See [NewRingEmitter] for details on the lifecycle of the lossy emitters.
+func NewCoalescingEmitter[T any, K comparable](
+	ctx context.Context,
+	downstream types.Emitter[T],
+	key func(T) K,
+) types.LossyEmitter[T] {
+	return newLossyEmitter[T](ctx, downstream, &coalescer[T, K]{key: key, values: make(map[K]T)})
+}
+
+// lossyQueue buffers the values of a [lossyEmitter], its methods are called with the mutex of the emitter held.
+type lossyQueue[T any] interface {
+	// push buffers the value, returning true if a value was dropped.
+	push(value T) bool
+	// pop removes the next value, returning false if no value is buffered.
+	pop() (T, bool)
+}
+
+// lossyEmitter implements [types.LossyEmitter].
+type lossyEmitter[T any] struct {
+	downstream types.Emitter[T]
+	dropped    *atomic.Uint64
+	// ready is signaled once a value is buffered or the emitter is closed.
+	ready chan struct{}
+	// done is closed once the forwarding goroutine returns.
+	done      chan struct{}
+	closeOnce *sync.Once
+
+	// mu protects the fields below.
+	mu     *sync.Mutex
+	queue  lossyQueue[T]
+	closed bool
+	// err is the error which stopped the forwarding goroutine.
+	err error
+}
+
+// newLossyEmitter creates a lossyEmitter and starts its forwarding goroutine.
+func newLossyEmitter[T any](ctx context.Context, downstream types.Emitter[T], queue lossyQueue[T]) *lossyEmitter[T] {
+	e := &lossyEmitter[T]{
+		downstream: downstream,
+		dropped:    &atomic.Uint64{},
+		ready:      make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		closeOnce:  &sync.Once{},
+		mu:         &sync.Mutex{},
+		queue:      queue,
+	}
+	go e.forward(ctx)
+
+	return e
+}
+
+// Emit implements [types.Emitter.Emit].
+// It buffers the value without blocking, dropping a value if needed.
+func (e *lossyEmitter[T]) Emit(ctx context.Context, value T) error {
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		panic("attempt to emit to a closed results.LossyEmitter")
+	}
+	if e.err != nil {
+		return e.err
+	}
+	if e.queue.push(value) {
+		e.dropped.Add(1)
+	}
+	e.signal()
+
+	return nil
+}
+
+// Dropped implements [types.LossyEmitter.Dropped].
+func (e *lossyEmitter[T]) Dropped() uint64 {
+	return e.dropped.Load()
+}
+
+// Close implements [types.Emitter.Close].
+// It waits for the buffered values to be forwarded, without closing the downstream emitter.
+func (e *lossyEmitter[T]) Close() {
+	e.closeOnce.Do(func() {
+		e.mu.Lock()
+		e.closed = true
+		e.signal()
+		e.mu.Unlock()
+	})
+	<-e.done
+}
+
+// signal wakes up the forwarding goroutine, it must be called with the mutex held.
+func (e *lossyEmitter[T]) signal() {
+	select {
+	case e.ready <- struct{}{}:
+	default:
+	}
+}
+
+// forward emits the buffered values to the downstream emitter, until the emitter is closed and the buffer is empty.
+func (e *lossyEmitter[T]) forward(ctx context.Context) {
+	defer close(e.done)
+
+	for {
+		e.mu.Lock()
+		value, ok := e.queue.pop()
+		closed := e.closed
+		e.mu.Unlock()
+
+		if !ok {
+			if closed {
+				return
+			}
+			select {
+			case <-e.ready:
+				continue
+			case <-ctx.Done():
+				e.fail(context.Cause(ctx))
+
+				return
+			}
+		}
+		if err := e.downstream.Emit(ctx, value); err != nil {
+			e.fail(err)
+
+			return
+		}
+	}
+}
+
+// fail records the error which stopped the forwarding goroutine.
+func (e *lossyEmitter[T]) fail(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.err = err
+}
+
+// ring is a bounded [lossyQueue] which drops either the newest or the oldest value once full.
+type ring[T any] struct {
+	values []T
+	head   int
+	len    int
+	// overwrite drops the oldest value instead of the newest one.
+	overwrite bool
+}
+
+// newRing creates a ring of the size.
+func newRing[T any](size uint, overwrite bool) *ring[T] {
+	if size == 0 {
+		panic("lossy emitter size must be greater than 0")
+	}
+
+	return &ring[T]{values: make([]T, size), overwrite: overwrite}
+}
+
+// push implements [lossyQueue.push].
+func (r *ring[T]) push(value T) bool {
+	dropped := false
+	if r.len == len(r.values) {
+		if !r.overwrite {
+			return true
+		}
+		r.pop()
+		dropped = true
+	}
+	r.values[(r.head+r.len)%len(r.values)] = value
+	r.len++
+
+	return dropped
+}
+
+// pop implements [lossyQueue.pop].
+func (r *ring[T]) pop() (T, bool) {
+	var zero T
+	if r.len == 0 {
+		return zero, false
+	}
+	value := r.values[r.head]
+	// The value is cleared, so that it can be garbage collected.
+	r.values[r.head] = zero
+	r.head = (r.head + 1) % len(r.values)
+	r.len--
+
+	return value, true
+}
+
+// coalescer is a [lossyQueue] holding the latest value of each key, in order of the first buffered value of the key.
+type coalescer[T any, K comparable] struct {
+	key    func(T) K
+	order  []K
+	values map[K]T
+}
+
+// push implements [lossyQueue.push].
+func (c *coalescer[T, K]) push(value T) bool {
+	k := c.key(value)
+	_, replaced := c.values[k]
+	if !replaced {
+		c.order = append(c.order, k)
+	}
+	c.values[k] = value
+
+	return replaced
+}
+
+// pop implements [lossyQueue.pop].
+func (c *coalescer[T, K]) pop() (T, bool) {
+	if len(c.order) == 0 {
+		var zero T
+
+		return zero, false
+	}
+	k := c.order[0]
+	c.order = c.order[1:]
+	value := c.values[k]
+	delete(c.values, k)
+
+	return value, true
+}
@@ -0,0 +1,134 @@
+package results
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// gateEmitter records the emitted values, blocking each of them until the gate is opened.
+type gateEmitter[T any] struct {
+	emitted chan T
+	gate    chan struct{}
+}
+
+func newGateEmitter[T any]() *gateEmitter[T] {
+	return &gateEmitter[T]{emitted: make(chan T, 100), gate: make(chan struct{})}
+}
+
+func (e *gateEmitter[T]) Emit(ctx context.Context, value T) error {
+	e.emitted <- value
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-e.gate:
+		return nil
+	}
+}
+
+func (e *gateEmitter[T]) Close() {
+	close(e.emitted)
+}
+
+// testLossyEmitter emits the values once the first one is blocked downstream, then closes the lossy emitter.
+// It returns the values received downstream.
+func testLossyEmitter[T any](t *testing.T, newEmitter func(types.Emitter[T]) types.LossyEmitter[T], values ...T) []T {
+	t.Helper()
+
+	downstream := newGateEmitter[T]()
+	e := newEmitter(downstream)
+	received := make([]T, 0, len(values))
+	for i, value := range values {
+		if err := e.Emit(context.Background(), value); err != nil {
+			t.Fatalf("emit returned error: %v", err)
+		}
+		if i == 0 {
+			received = append(received, <-downstream.emitted)
+		}
+	}
+	close(downstream.gate)
+	e.Close()
+	downstream.Close()
+	for value := range downstream.emitted {
+		received = append(received, value)
+	}
+
+	return received
+}
+
+func TestDropNewestEmitter(t *testing.T) {
+	var e types.LossyEmitter[int]
+	received := testLossyEmitter(t, func(downstream types.Emitter[int]) types.LossyEmitter[int] {
+		e = NewDropNewestEmitter(context.Background(), downstream, 2)
+
+		return e
+	}, 1, 2, 3, 4, 5)
+	if expected := []int{1, 2, 3}; !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected %v, got %v", expected, received)
+	}
+	if dropped := e.Dropped(); dropped != 2 {
+		t.Errorf("expected 2 dropped values, got %d", dropped)
+	}
+}
+
+func TestRingEmitter(t *testing.T) {
+	var e types.LossyEmitter[int]
+	received := testLossyEmitter(t, func(downstream types.Emitter[int]) types.LossyEmitter[int] {
+		e = NewRingEmitter(context.Background(), downstream, 2)
+
+		return e
+	}, 1, 2, 3, 4, 5)
+	if expected := []int{1, 4, 5}; !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected %v, got %v", expected, received)
+	}
+	if dropped := e.Dropped(); dropped != 2 {
+		t.Errorf("expected 2 dropped values, got %d", dropped)
+	}
+}
+
+func TestCoalescingEmitter(t *testing.T) {
+	type gauge struct {
+		name  string
+		value int
+	}
+	var e types.LossyEmitter[gauge]
+	received := testLossyEmitter(t, func(downstream types.Emitter[gauge]) types.LossyEmitter[gauge] {
+		e = NewCoalescingEmitter(context.Background(), downstream, func(g gauge) string { return g.name })
+
+		return e
+	}, gauge{"cpu", 1}, gauge{"cpu", 2}, gauge{"mem", 3}, gauge{"cpu", 4}, gauge{"mem", 5})
+	expected := []gauge{{"cpu", 1}, {"cpu", 4}, {"mem", 5}}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected %v, got %v", expected, received)
+	}
+	if dropped := e.Dropped(); dropped != 2 {
+		t.Errorf("expected 2 dropped values, got %d", dropped)
+	}
+}
+
+func TestLossyEmitterContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	downstream := newGateEmitter[int]()
+	e := NewRingEmitter[int](ctx, downstream, 1)
+	if err := e.Emit(ctx, 1); err != nil {
+		t.Fatalf("emit returned error: %v", err)
+	}
+	<-downstream.emitted
+
+	// Close returns without forwarding the buffered values once the context is canceled.
+	if err := e.Emit(ctx, 2); err != nil {
+		t.Fatalf("emit returned error: %v", err)
+	}
+	cancel()
+	e.Close()
+	downstream.Close()
+	for value := range downstream.emitted {
+		t.Errorf("unexpected value forwarded after cancelation: %d", value)
+	}
+	if err := e.Emit(ctx, 3); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package results
+
+import (
+	"context"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// NewResultEmitter wraps an [types.Emitter] of [types.Result] into a [types.ResultEmitter].
+func NewResultEmitter[T any](emitter types.Emitter[types.Result[T]]) types.ResultEmitter[T] {
+	return &resultEmitter[T]{Emitter: emitter}
+}
+
+// resultEmitter implements [types.ResultEmitter].
+type resultEmitter[T any] struct {
+	types.Emitter[types.Result[T]]
+}
+
+// EmitValue implements [types.ResultEmitter.EmitValue].
+func (e *resultEmitter[T]) EmitValue(ctx context.Context, value T) error {
+	//nolint:wrapcheck
+	return e.Emit(ctx, types.Result[T]{Value: value})
+}
+
+// EmitError implements [types.ResultEmitter.EmitError].
+func (e *resultEmitter[T]) EmitError(ctx context.Context, err error) error {
+	//nolint:wrapcheck
+	return e.Emit(ctx, types.Result[T]{Err: err})
+}
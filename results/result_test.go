@@ -0,0 +1,34 @@
+package results
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+func TestResultEmitter(t *testing.T) {
+	resultsChan := make(chan types.Result[int], 2)
+	h := NewResultEmitter[int](NewEmitter(resultsChan))
+
+	ctx := context.Background()
+	itemErr := errors.New("item error")
+	if err := h.EmitValue(ctx, 42); err != nil {
+		t.Fatalf("emit returned error: %v", err)
+	}
+	if err := h.EmitError(ctx, itemErr); err != nil {
+		t.Fatalf("emit returned error: %v", err)
+	}
+	h.Close()
+
+	if result := <-resultsChan; result.Value != 42 || result.Err != nil {
+		t.Errorf("expected value 42, got %+v", result)
+	}
+	if result := <-resultsChan; !errors.Is(result.Err, itemErr) {
+		t.Errorf("expected the item error, got %+v", result)
+	}
+	if _, ok := <-resultsChan; ok {
+		t.Error("expected the results channel to be closed")
+	}
+}
@@ -0,0 +1,304 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+)
+
+// Stream identifies the output stream of a [Line].
+type Stream uint8
+
+const (
+	// Stdout is the standard output of the command.
+	Stdout Stream = iota + 1
+	// Stderr is the standard error of the command.
+	Stderr
+)
+
+// String implements [fmt.Stringer] for [Stream].
+func (s Stream) String() string {
+	switch s {
+	case Stdout:
+		return "stdout"
+	case Stderr:
+		return "stderr"
+	default:
+		return fmt.Sprintf("Stream(%d)", uint8(s))
+	}
+}
+
+// Line is a line of output of a [Command], without its trailing newline.
+type Line struct {
+	Stream Stream
+	Text   string
+}
+
+// Limits are the resource limits of a [Command], see setrlimit(2).
+// Each limit is applied as both the soft and hard limit, and is not applied if 0.
+//
+// Limits are only supported on Linux, where they are applied before the program is executed, and are inherited by its
+// children.
+// They must be enabled by calling [ExecIfLimited] early in the main function of the program.
+type Limits struct {
+	// CPUTime is the CPU time of the process (RLIMIT_CPU), rounded up to the second.
+	CPUTime time.Duration
+	// AddressSpace is the size of the virtual memory of the process in bytes (RLIMIT_AS).
+	AddressSpace uint64
+	// FileSize is the size of the files the process may create in bytes (RLIMIT_FSIZE).
+	FileSize uint64
+	// OpenFiles is the number of files the process may open (RLIMIT_NOFILE).
+	OpenFiles uint64
+}
+
+// limitsEnv is set in the environment of the current binary when it is re-executed to apply the [Limits] of a
+// command, its value is the encoded limits.
+const limitsEnv = "SAFECONCURRENCY_COMMAND_LIMITS"
+
+// limitsEnabled is set by [ExecIfLimited], the [Limits] of the commands cannot be applied without it.
+//
+//nolint:gochecknoglobals
+var limitsEnabled atomic.Bool
+
+// ExecIfLimited applies the [Limits] of a [Command] and executes its program, if the current process is the binary
+// re-executed to start the command.
+// Otherwise, it returns immediately, and enables the [Limits] of the commands started by the current process.
+//
+// As setrlimit(2) cannot be called between fork and exec in Go, a command with [Limits] is started by re-executing the
+// current binary (see [os.Executable]), which applies the limits to itself before executing the program.
+// ExecIfLimited must therefore be called early in the main function of the program (or in TestMain), before any side
+// effects which should not be repeated for each command.
+// The commands with [Limits] fail with [safeconcurrencyerrors.ErrLimitsDisabled] if it was not called.
+func ExecIfLimited() {
+	if encoded, ok := os.LookupEnv(limitsEnv); ok {
+		execLimited(encoded)
+	}
+	limitsEnabled.Store(true)
+}
+
+// isZero returns true if no limit is set.
+func (l Limits) isZero() bool {
+	return l == Limits{}
+}
+
+// ExitError is returned by a [Command] which exited unsuccessfully.
+type ExitError struct {
+	// Name is the name of the command.
+	Name string
+	// Code is the exit code of the command, or -1 if it was terminated by a signal.
+	Code int
+	// Err is the underlying error.
+	Err *exec.ExitError
+}
+
+// Error implements the error interface for [ExitError].
+func (e *ExitError) Error() string {
+	if e.Code < 0 {
+		return fmt.Sprintf("command %s terminated: %v", e.Name, e.Err)
+	}
+
+	return fmt.Sprintf("command %s exited with code %d", e.Name, e.Code)
+}
+
+// Unwrap returns the underlying [exec.ExitError].
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Command is a [types.StreamingTask] executing an external program, and emitting each [Line] of its standard output
+// and standard error.
+// The lines of the two streams are emitted concurrently, and their relative order is not preserved.
+//
+// When the [context.Context] of the task is canceled, the process group of the command is sent SIGTERM, and SIGKILL
+// once [Command.GracePeriod] elapses.
+// Once the canceled command exited, the processes remaining in its process group are killed.
+// On platforms without process groups or signals, the process is killed immediately.
+//
+// If the command exits unsuccessfully, an [*ExitError] is returned, unless the context was canceled, in which case the
+// [context.Cause] is returned.
+type Command[ResourceT any] struct {
+	// Name is the program to execute, it is looked up in the PATH if it contains no path separator.
+	Name string
+	// Args are the arguments of the program, not including its name.
+	Args []string
+	// Dir is the working directory of the command, the working directory of the current process is used if empty.
+	Dir string
+	// Env is the environment of the command, the environment of the current process is used if nil.
+	Env []string
+	// Stdin is the standard input of the command, the null device is used if nil.
+	Stdin io.Reader
+
+	// GracePeriod is the time between SIGTERM and SIGKILL sent to the process group when the task is canceled.
+	// The command is killed immediately if 0.
+	GracePeriod time.Duration
+	// MaxLineSize is the maximum size of a [Line] in bytes, [bufio.MaxScanTokenSize] is used if 0.
+	MaxLineSize int
+	// Limits are the resource limits of the command.
+	Limits Limits
+	// SysProcAttr holds optional, operating system-specific attributes of the command, see [exec.Cmd.SysProcAttr].
+	// On Unix platforms, the command is always started in a new process group.
+	SysProcAttr *syscall.SysProcAttr
+}
+
+// New creates a [Command] executing the program with the arguments.
+func New[ResourceT any](name string, args ...string) *Command[ResourceT] {
+	return &Command[ResourceT]{Name: name, Args: args}
+}
+
+// Execute implements [types.StreamingTask.Execute].
+func (c *Command[ResourceT]) Execute(ctx context.Context, _ ResourceT, emitter types.Emitter[Line]) error {
+	if !c.Limits.isZero() && !limitsSupported {
+		return fmt.Errorf("command %s: %w", c.Name, safeconcurrencyerrors.ErrLimitsUnsupported)
+	}
+	if !c.Limits.isZero() && !limitsEnabled.Load() {
+		return fmt.Errorf("command %s: %w", c.Name, safeconcurrencyerrors.ErrLimitsDisabled)
+	}
+
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Env = c.Env
+	cmd.Stdin = c.Stdin
+	if c.SysProcAttr != nil {
+		attr := *c.SysProcAttr
+		cmd.SysProcAttr = &attr
+	}
+	killGroup := configureCancel(cmd, c.GracePeriod)
+	var limits *limitsPipe
+	if !c.Limits.isZero() {
+		var err error
+		if limits, err = limitCommand(cmd, c.Limits); err != nil {
+			return fmt.Errorf("command %s: applying limits: %w", c.Name, err)
+		}
+	}
+
+	// The output is copied to pipes by the exec package, so that the grace period also bounds the time spent waiting
+	// for the output to be closed, for example by orphaned grandchildren.
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderrReader, stderrWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+
+	var wg sync.WaitGroup
+	scanErrs := make([]error, 2)
+	for i, pipe := range []struct {
+		stream Stream
+		reader *io.PipeReader
+	}{{Stdout, stdoutReader}, {Stderr, stderrReader}} {
+		i, pipe := i, pipe
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scanErrs[i] = c.scan(ctx, pipe.stream, pipe.reader, emitter)
+		}()
+	}
+	// closeOutput stops the readers once the command has exited.
+	closeOutput := func() {
+		_ = stdoutWriter.Close()
+		_ = stderrWriter.Close()
+		wg.Wait()
+	}
+
+	if err := cmd.Start(); err != nil {
+		if limits != nil {
+			limits.close()
+		}
+		closeOutput()
+
+		return fmt.Errorf("command %s: %w", c.Name, err)
+	}
+	if limits != nil {
+		if err := limits.wait(); err != nil {
+			_ = cmd.Wait()
+			killGroup()
+			closeOutput()
+
+			return fmt.Errorf("command %s: %w", c.Name, err)
+		}
+	}
+
+	waitErr := cmd.Wait()
+	killGroup()
+	closeOutput()
+
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		return &ExitError{Name: c.Name, Code: exitErr.ExitCode(), Err: exitErr}
+	}
+	if waitErr != nil {
+		return fmt.Errorf("command %s: %w", c.Name, waitErr)
+	}
+	if err := errors.Join(scanErrs...); err != nil {
+		return fmt.Errorf("command %s: reading output: %w", c.Name, err)
+	}
+
+	return nil
+}
+
+// scan emits the lines read from the output stream of the command.
+// The stream is drained if the emitter fails, so that the command does not block on its output.
+func (c *Command[ResourceT]) scan(ctx context.Context, stream Stream, r io.Reader, emitter types.Emitter[Line]) error {
+	scanner := bufio.NewScanner(r)
+	if c.MaxLineSize > 0 {
+		scanner.Buffer(nil, c.MaxLineSize)
+	}
+
+	for scanner.Scan() {
+		if err := emitter.Emit(ctx, Line{Stream: stream, Text: scanner.Text()}); err != nil {
+			_, _ = io.Copy(io.Discard, r)
+
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_, _ = io.Copy(io.Discard, r)
+
+		//nolint:wrapcheck
+		return err
+	}
+
+	return nil
+}
+
+// Run is a helper function to submit a [Command] to a [types.WorkerPool], and run the callback for each [Line] of its
+// output, see [workpool.SubmitStreaming].
+func Run[ResourceT any](
+	ctx context.Context,
+	pool types.WorkerPool[ResourceT],
+	cmd *Command[ResourceT],
+	callback workpool.ResultCallback[Line],
+) error {
+	return workpool.SubmitStreaming[ResourceT, Line](ctx, pool, cmd, callback)
+}
+
+// Output is a helper function to submit a [Command] to a [types.WorkerPool], and collect its output.
+// If the command fails, the output collected so far is returned with the error.
+func Output[ResourceT any](
+	ctx context.Context,
+	pool types.WorkerPool[ResourceT],
+	cmd *Command[ResourceT],
+) ([]Line, error) {
+	lines := make([]Line, 0)
+	err := Run(ctx, pool, cmd, func(_ context.Context, line Line) error {
+		lines = append(lines, line)
+
+		return nil
+	})
+
+	return lines, err
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+)
+
+func TestMain(m *testing.M) {
+	ExecIfLimited()
+	os.Exit(m.Run())
+}
+
+// startPool starts a pool for the commands, skipping the test in short mode as starting processes is slow, or if
+// there is no shell.
+func startPool(t *testing.T) types.WorkerPool[any] {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping command test in short mode")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("skipping command test without sh")
+	}
+
+	p := workpool.New[any](nil, 2)
+	p.Start()
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func TestOutput(t *testing.T) {
+	p := startPool(t)
+
+	cmd := New[any]("sh", "-c", "echo one; echo two >&2; echo three")
+	cmd.Stdin = strings.NewReader("ignored\n")
+	lines, err := Output(context.Background(), p, cmd)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var stdout, stderr []string
+	for _, line := range lines {
+		switch line.Stream {
+		case Stdout:
+			stdout = append(stdout, line.Text)
+		case Stderr:
+			stderr = append(stderr, line.Text)
+		}
+	}
+	if strings.Join(stdout, ",") != "one,three" {
+		t.Errorf("Expected stdout one,three, got %q", stdout)
+	}
+	if strings.Join(stderr, ",") != "two" {
+		t.Errorf("Expected stderr two, got %q", stderr)
+	}
+}
+
+func TestExitError(t *testing.T) {
+	p := startPool(t)
+
+	lines, err := Output(context.Background(), p, New[any]("sh", "-c", "echo partial; exit 3"))
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("Expected ExitError with code 3, got %v", err)
+	}
+	if len(lines) != 1 || lines[0].Text != "partial" {
+		t.Errorf("Expected the partial output, got %v", lines)
+	}
+
+	_, err = Output(context.Background(), p, New[any]("/nonexistent/command"))
+	if err == nil || errors.As(err, &exitErr) {
+		t.Errorf("Expected a start error, got %v", err)
+	}
+}
+
+func TestMaxLineSize(t *testing.T) {
+	p := startPool(t)
+
+	cmd := New[any]("sh", "-c", "echo 0123456789")
+	cmd.MaxLineSize = 4
+	if _, err := Output(context.Background(), p, cmd); err == nil {
+		t.Error("Expected an error for a line exceeding the maximum size")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	p := startPool(t)
+
+	for _, gracePeriod := range []time.Duration{0, 10 * time.Millisecond} {
+		// The command ignores SIGTERM, and must be killed.
+		cmd := New[any]("sh", "-c", `trap "" TERM; echo ready; while :; do :; done`)
+		cmd.GracePeriod = gracePeriod
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err := Run(ctx, p, cmd, func(_ context.Context, line Line) error {
+			if line.Text == "ready" {
+				cancel()
+			}
+
+			return nil
+		})
+		cancel()
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled with grace period %v, got %v", gracePeriod, err)
+		}
+	}
+}
+
+func TestStreamString(t *testing.T) {
+	names := []string{Stdout.String(), Stderr.String(), Stream(0).String()}
+	if strings.Join(names, ",") != "stdout,stderr,Stream(0)" {
+		t.Errorf("Unexpected stream names %q", names)
+	}
+}
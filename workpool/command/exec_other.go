@@ -0,0 +1,16 @@
+//go:build !unix
+
+package command
+
+import (
+	"os/exec"
+	"time"
+)
+
+// configureCancel kills the command when it is canceled, as process groups and signals are not supported.
+// The returned function does nothing.
+func configureCancel(cmd *exec.Cmd, gracePeriod time.Duration) func() {
+	cmd.WaitDelay = gracePeriod
+
+	return func() {}
+}
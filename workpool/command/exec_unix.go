@@ -0,0 +1,60 @@
+//go:build unix
+
+package command
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// configureCancel starts the command in a new process group, which is sent SIGTERM when the command is canceled, and
+// SIGKILL once the grace period elapses, or immediately if there is no grace period.
+// The returned function must be called once the command exited: if it was canceled, the processes remaining in its
+// process group are killed, so that none outlives the command.
+func configureCancel(cmd *exec.Cmd, gracePeriod time.Duration) func() {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.SysProcAttr.Pgid = 0
+
+	kill := func(signal syscall.Signal) error {
+		// A negative pid signals the process group.
+		err := syscall.Kill(-cmd.Process.Pid, signal)
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+
+		//nolint:wrapcheck
+		return err
+	}
+
+	// escalation sends SIGKILL to the process group once the grace period elapses, it is protected by mu.
+	// [exec.Cmd.Wait] only kills the process itself once the grace period elapses.
+	var mu sync.Mutex
+	var escalation *time.Timer
+	cmd.Cancel = func() error {
+		if gracePeriod <= 0 {
+			return kill(syscall.SIGKILL)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		escalation = time.AfterFunc(gracePeriod, func() { _ = kill(syscall.SIGKILL) })
+
+		return kill(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = gracePeriod
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if escalation != nil && escalation.Stop() {
+			_ = kill(syscall.SIGKILL)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+//go:build unix
+
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCancelKillsProcessGroup(t *testing.T) {
+	p := startPool(t)
+
+	// The child ignores SIGTERM and outlives the command, it must be killed with the process group.
+	marker := filepath.Join(t.TempDir(), "alive")
+	cmd := New[any]("sh", "-c", `trap "" TERM; (sleep 1; touch "$0") & echo ready; wait`, marker)
+	cmd.GracePeriod = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := Run(ctx, p, cmd, func(_ context.Context, line Line) error {
+		if line.Text == "ready" {
+			cancel()
+		}
+
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	time.Sleep(2 * time.Second)
+	if _, err := os.Stat(marker); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the child to be killed, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+//go:build linux
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// limitsSupported is true if [Limits] can be applied on the current platform.
+const limitsSupported = true
+
+// limitsErrFD is the file descriptor of the re-executed binary reporting the failure to apply the limits or to
+// execute the program.
+// It is closed on exec, so that the parent reads EOF once the program is executed.
+const limitsErrFD = 3
+
+// limitsPipe receives the failure to apply the limits from the re-executed binary.
+type limitsPipe struct {
+	r *os.File
+	w *os.File
+}
+
+// limitCommand configures the command to be started by re-executing the current binary, which applies the limits with
+// setrlimit(2) and then executes the program, see [ExecIfLimited].
+// It returns nil if the program could not be found, in which case [exec.Cmd.Start] returns the error.
+func limitCommand(cmd *exec.Cmd, limits Limits) (*limitsPipe, error) {
+	if cmd.Err != nil {
+		return nil, nil //nolint:nilnil
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locating executable: %w", err)
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env[:len(env):len(env)], limitsEnv+"="+encodeLimits(limits))
+	// The arguments of the re-executed binary are the path of the program, followed by its arguments.
+	cmd.Args = append([]string{self, cmd.Path}, cmd.Args...)
+	cmd.Path = self
+	cmd.ExtraFiles = []*os.File{w}
+
+	return &limitsPipe{r: r, w: w}, nil
+}
+
+// wait waits for the re-executed binary to execute the program, returning the failure to apply the limits if any.
+// It must be called once the command is started.
+func (p *limitsPipe) wait() error {
+	_ = p.w.Close()
+	defer p.r.Close()
+
+	msg, err := io.ReadAll(p.r)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	if len(msg) > 0 {
+		//nolint:err113
+		return errors.New(string(msg))
+	}
+
+	return nil
+}
+
+// close releases the pipe if the command could not be started.
+func (p *limitsPipe) close() {
+	_ = p.w.Close()
+	_ = p.r.Close()
+}
+
+// encodeLimits encodes the limits for [limitsEnv].
+func encodeLimits(limits Limits) string {
+	cpuTime := uint64((limits.CPUTime + time.Second - 1) / time.Second)
+	values := []string{}
+	for _, value := range []uint64{cpuTime, limits.AddressSpace, limits.FileSize, limits.OpenFiles} {
+		values = append(values, strconv.FormatUint(value, 10))
+	}
+
+	return strings.Join(values, ",")
+}
+
+// execLimited applies the encoded limits to the current process, and executes the program in its arguments, see
+// [ExecIfLimited].
+// It never returns: on failure, the error is written to [limitsErrFD] and the process exits.
+func execLimited(encoded string) {
+	errPipe := os.NewFile(limitsErrFD, "limits")
+	fail := func(err error) {
+		_, _ = fmt.Fprintf(errPipe, "applying limits: %v", err)
+		os.Exit(127)
+	}
+
+	fields := strings.Split(encoded, ",")
+	if len(fields) != 4 || len(os.Args) < 3 {
+		fail(errors.New("invalid re-execution")) //nolint:err113
+	}
+	env := make([]string, 0, len(os.Environ()))
+	for _, v := range os.Environ() {
+		if !strings.HasPrefix(v, limitsEnv+"=") {
+			env = append(env, v)
+		}
+	}
+	syscall.CloseOnExec(limitsErrFD)
+
+	for i, resource := range []int{syscall.RLIMIT_CPU, syscall.RLIMIT_AS, syscall.RLIMIT_FSIZE, syscall.RLIMIT_NOFILE} {
+		value, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			fail(err)
+		}
+		if value == 0 {
+			continue
+		}
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: value, Max: value}); err != nil {
+			fail(err)
+		}
+	}
+
+	//nolint:gosec // The program is the one configured by the parent process.
+	err := syscall.Exec(os.Args[1], os.Args[2:], env)
+	fail(fmt.Errorf("executing %s: %w", os.Args[1], err))
+}
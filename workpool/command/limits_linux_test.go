@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+)
+
+func TestLimits(t *testing.T) {
+	p := startPool(t)
+
+	// The limits are applied before the shell is executed, and are inherited by its children.
+	cmd := New[any]("sh", "-c", `ulimit -n; sh -c "ulimit -n"`)
+	cmd.Limits = Limits{OpenFiles: 64}
+	lines, err := Output(context.Background(), p, cmd)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Line{{Stream: Stdout, Text: "64"}, {Stream: Stdout, Text: "64"}}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %v, got %v", expected, lines)
+	}
+}
+
+func TestLimitsFailure(t *testing.T) {
+	p := startPool(t)
+
+	// The program is not executed if the limits cannot be applied.
+	cmd := New[any]("sh", "-c", "echo executed")
+	cmd.Limits = Limits{OpenFiles: 1 << 62}
+	lines, err := Output(context.Background(), p, cmd)
+	if err == nil {
+		t.Error("Expected an error applying the limits")
+	}
+	if len(lines) != 0 {
+		t.Errorf("Expected no output, got %v", lines)
+	}
+}
+
+func TestLimitsDisabled(t *testing.T) {
+	p := startPool(t)
+
+	limitsEnabled.Store(false)
+	defer limitsEnabled.Store(true)
+
+	// The current binary is not re-executed if the program did not call ExecIfLimited.
+	cmd := New[any]("sh", "-c", "echo executed")
+	cmd.Limits = Limits{OpenFiles: 64}
+	lines, err := Output(context.Background(), p, cmd)
+	if !errors.Is(err, safeconcurrencyerrors.ErrLimitsDisabled) {
+		t.Errorf("Expected ErrLimitsDisabled, got %v", err)
+	}
+	if len(lines) != 0 {
+		t.Errorf("Expected no output, got %v", lines)
+	}
+}
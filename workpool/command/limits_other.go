@@ -0,0 +1,31 @@
+//go:build !linux
+
+package command
+
+import (
+	"os/exec"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+)
+
+// limitsSupported is true if [Limits] can be applied on the current platform.
+const limitsSupported = false
+
+// limitsPipe is never used, as [Limits] are not supported on the current platform.
+type limitsPipe struct{}
+
+// limitCommand is never called, as [Limits] are not supported on the current platform.
+func limitCommand(*exec.Cmd, Limits) (*limitsPipe, error) {
+	return nil, safeconcurrencyerrors.ErrLimitsUnsupported
+}
+
+// wait is never called, as [Limits] are not supported on the current platform.
+func (*limitsPipe) wait() error {
+	return nil
+}
+
+// execLimited is never called, as [Limits] are not supported on the current platform.
+func execLimited(string) {}
+
+// close is never called, as [Limits] are not supported on the current platform.
+func (*limitsPipe) close() {}
@@ -0,0 +1,410 @@
+package cron
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+)
+
+// JobID identifies a job added to a [Cron].
+type JobID uint64
+
+// OverlapPolicy selects what happens when a job is due while its previous run is still in progress.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the run if the previous run is still in progress.
+	OverlapSkip OverlapPolicy = iota
+
+	// OverlapQueue delays the run until the previous runs have finished, the runs of the job are never concurrent.
+	OverlapQueue
+
+	// OverlapCancelPrevious cancels the [context.Context] of the previous run with
+	// [safeconcurrencyerrors.ErrRunSuperseded], and starts the new run immediately.
+	OverlapCancelPrevious
+)
+
+// Config configures a [Cron].
+type Config struct {
+	// Clock is used to determine when jobs are due, the [clock.Real] clock is used if nil.
+	Clock types.Clock
+
+	// Location is the [time.Location] in which expressions without a time zone are interpreted.
+	// The [time.Local] location is used if nil.
+	Location *time.Location
+
+	// Rand returns a pseudo-random number in [0, n) used to apply [JobConfig.Jitter].
+	// The [math/rand.Int63n] function is used if nil.
+	Rand func(n int64) int64
+}
+
+// JobConfig configures a job added to a [Cron].
+type JobConfig struct {
+	// Overlap selects what happens when the job is due while its previous run is still in progress, [OverlapSkip] by
+	// default.
+	Overlap OverlapPolicy
+
+	// Jitter delays each run by a random duration in [0, Jitter), to avoid many jobs hitting shared resources at once.
+	Jitter time.Duration
+
+	// Timeout limits the duration of each run, if greater than 0.
+	Timeout time.Duration
+
+	// OnError is called with the error of each failed run, including runs canceled by [OverlapCancelPrevious].
+	OnError func(JobID, error)
+}
+
+// Entry describes a job added to a [Cron], as returned by [Cron.Entries].
+type Entry struct {
+	ID JobID
+	// Spec is the cron expression of the job.
+	Spec string
+	// Next is the time of the next run, including jitter.
+	Next time.Time
+	// Running is the number of runs in progress.
+	Running int
+	// Queued is the number of runs waiting for the previous runs to finish, see [OverlapQueue].
+	Queued int
+}
+
+// Cron submits tasks to a [types.WorkerPool] on schedules described by cron expressions.
+// A single goroutine and timer are used to trigger all jobs.
+// Use [Add] or [AddSchedule] to add jobs.
+//
+// Each run is submitted with [workpool.Submit], and thus waits for a worker of the pool to be available.
+// The [types.WorkerPool] must remain open until the [Cron] is closed.
+type Cron[ResourceT any] struct {
+	pool     types.WorkerPool[ResourceT]
+	clock    types.Clock
+	location *time.Location
+	rand     func(int64) int64
+
+	// mu protects jobs, nextID, and closed, as well as the state of each job.
+	mu     sync.Mutex
+	jobs   map[JobID]*job
+	nextID JobID
+	closed bool
+
+	// wake is signaled when a job is added or the cron is closed.
+	wake      chan struct{}
+	done      chan struct{}
+	runs      *sync.WaitGroup
+	started   *atomic.Bool
+	closeOnce *sync.Once
+}
+
+// New creates (but does not start) a [Cron] submitting tasks to the [types.WorkerPool].
+func New[ResourceT any](pool types.WorkerPool[ResourceT], config Config) *Cron[ResourceT] {
+	location := config.Location
+	if location == nil {
+		location = time.Local
+	}
+	random := config.Rand
+	if random == nil {
+		random = rand.Int63n
+	}
+
+	return &Cron[ResourceT]{
+		pool:      pool,
+		clock:     clock.OrReal(config.Clock),
+		location:  location,
+		rand:      random,
+		jobs:      make(map[JobID]*job),
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		runs:      &sync.WaitGroup{},
+		started:   &atomic.Bool{},
+		closeOnce: &sync.Once{},
+	}
+}
+
+// Add parses the cron expression in the [Config.Location] (see [ParseInLocation]) and adds a job submitting the
+// [types.Task] to the [types.WorkerPool] of the [Cron] on schedule.
+// The values produced by the task are discarded, errors are reported to [JobConfig.OnError].
+func Add[ResourceT any, ValueT any](
+	c *Cron[ResourceT],
+	spec string,
+	tsk types.Task[ResourceT, ValueT],
+	config JobConfig,
+) (JobID, error) {
+	schedule, err := ParseInLocation(spec, c.location)
+	if err != nil {
+		return 0, err
+	}
+
+	return AddSchedule(c, spec, schedule, tsk, config), nil
+}
+
+// AddSchedule adds a job submitting the [types.Task] to the [types.WorkerPool] of the [Cron] on the [Schedule].
+// The spec is only used to describe the job in [Cron.Entries].
+func AddSchedule[ResourceT any, ValueT any](
+	c *Cron[ResourceT],
+	spec string,
+	schedule *Schedule,
+	tsk types.Task[ResourceT, ValueT],
+	config JobConfig,
+) JobID {
+	j := &job{
+		spec:     spec,
+		schedule: schedule,
+		config:   config,
+		run: func(ctx context.Context) error {
+			_, err := workpool.Submit(ctx, c.pool, tsk)
+
+			return err
+		},
+	}
+
+	c.mu.Lock()
+	c.nextID++
+	j.id = c.nextID
+	c.scheduleNext(j, c.clock.Now())
+	c.jobs[j.id] = j
+	c.mu.Unlock()
+
+	c.signal()
+
+	return j.id
+}
+
+// Remove removes the job from the [Cron], runs in progress are not canceled but queued runs are discarded.
+// It returns false if the job does not exist.
+func (c *Cron[ResourceT]) Remove(id JobID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	j, ok := c.jobs[id]
+	if ok {
+		j.queued = 0
+		delete(c.jobs, id)
+	}
+
+	return ok
+}
+
+// Entries lists the jobs of the [Cron], ordered by their next run time.
+func (c *Cron[ResourceT]) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]Entry, 0, len(c.jobs))
+	for _, j := range c.jobs {
+		entries = append(entries, Entry{ID: j.id, Spec: j.spec, Next: j.fireAt, Running: j.running, Queued: j.queued})
+	}
+	sort.Slice(entries, func(i, k int) bool {
+		if entries[i].Next.Equal(entries[k].Next) {
+			return entries[i].ID < entries[k].ID
+		}
+
+		return entries[i].Next.Before(entries[k].Next)
+	})
+
+	return entries
+}
+
+// NextRuns returns the next n scheduled run times of the job, without jitter.
+// It returns nil if the job does not exist.
+func (c *Cron[ResourceT]) NextRuns(id JobID, n int) []time.Time {
+	c.mu.Lock()
+	j, ok := c.jobs[id]
+	c.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	return j.schedule.NextN(c.clock.Now(), n)
+}
+
+// Start launches the goroutine triggering the jobs.
+func (c *Cron[ResourceT]) Start() {
+	if c.started.Swap(true) {
+		panic("attempt to start previously started cron.Cron")
+	}
+
+	go c.loop()
+}
+
+// Close stops triggering jobs, discards queued runs, and waits for the runs in progress to finish.
+// It is safe to call Close multiple times, or before Start.
+func (c *Cron[ResourceT]) Close() {
+	c.closeOnce.Do(c.close)
+}
+
+// close does not synchronize with [Cron.closeOnce].
+func (c *Cron[ResourceT]) close() {
+	c.mu.Lock()
+	c.closed = true
+	for _, j := range c.jobs {
+		j.queued = 0
+	}
+	c.mu.Unlock()
+
+	if c.started.Load() {
+		c.signal()
+		<-c.done
+	}
+	c.runs.Wait()
+}
+
+// signal wakes the cron goroutine without blocking.
+func (c *Cron[ResourceT]) signal() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop is the goroutine launched by [Cron.Start].
+func (c *Cron[ResourceT]) loop() {
+	defer close(c.done)
+
+	timer := c.clock.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+
+			return
+		}
+
+		now := c.clock.Now()
+		var earliest time.Time
+		for _, j := range c.jobs {
+			if j.fireAt.IsZero() {
+				// The schedule never matches again.
+				continue
+			}
+			if !j.fireAt.After(now) {
+				c.trigger(j)
+				c.scheduleNext(j, now)
+			}
+			if !j.fireAt.IsZero() && (earliest.IsZero() || j.fireAt.Before(earliest)) {
+				earliest = j.fireAt
+			}
+		}
+
+		var wait <-chan time.Time
+		if !earliest.IsZero() {
+			timer.Reset(earliest.Sub(now))
+			wait = timer.C()
+		} else {
+			timer.Stop()
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-c.wake:
+		}
+	}
+}
+
+// scheduleNext computes the next run of the job after the provided time, applying the jitter.
+// Runs missed while the cron was not able to trigger the job are skipped.
+// The caller must hold the lock.
+func (c *Cron[ResourceT]) scheduleNext(j *job, after time.Time) {
+	next := j.schedule.Next(after)
+	if !next.IsZero() && j.config.Jitter > 0 {
+		next = next.Add(time.Duration(c.rand(int64(j.config.Jitter))))
+	}
+	j.fireAt = next
+}
+
+// trigger starts a run of the job according to its overlap policy.
+// The caller must hold the lock.
+func (c *Cron[ResourceT]) trigger(j *job) {
+	if j.running > 0 {
+		switch j.config.Overlap {
+		case OverlapSkip:
+			return
+		case OverlapQueue:
+			j.queued++
+
+			return
+		case OverlapCancelPrevious:
+			for _, run := range j.runs {
+				run.cancel(safeconcurrencyerrors.ErrRunSuperseded)
+			}
+		}
+	}
+
+	c.start(j)
+}
+
+// start launches a run of the job.
+// The caller must hold the lock.
+func (c *Cron[ResourceT]) start(j *job) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	run := &jobRun{cancel: cancel}
+	j.running++
+	j.runs = append(j.runs, run)
+	c.runs.Add(1)
+
+	go func() {
+		defer c.runs.Done()
+		defer cancel(context.Canceled)
+
+		runCtx := ctx
+		if j.config.Timeout > 0 {
+			var cancelTimeout context.CancelFunc
+			runCtx, cancelTimeout = context.WithTimeout(ctx, j.config.Timeout)
+			defer cancelTimeout()
+		}
+
+		err := j.run(runCtx)
+		if err != nil && j.config.OnError != nil {
+			j.config.OnError(j.id, err)
+		}
+
+		c.finish(j, run)
+	}()
+}
+
+// finish records the end of a run of the job, starting a queued run if any.
+func (c *Cron[ResourceT]) finish(j *job, run *jobRun) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	j.running--
+	for i, running := range j.runs {
+		if running == run {
+			j.runs = append(j.runs[:i], j.runs[i+1:]...)
+
+			break
+		}
+	}
+	if j.queued > 0 && !c.closed {
+		j.queued--
+		c.start(j)
+	}
+}
+
+// job is the state of a job added to a [Cron].
+type job struct {
+	id       JobID
+	spec     string
+	schedule *Schedule
+	config   JobConfig
+	run      func(context.Context) error
+
+	// fireAt is the time of the next run including jitter, or the zero time if the schedule never matches again.
+	fireAt  time.Time
+	running int
+	queued  int
+	runs    []*jobRun
+}
+
+// jobRun is a single run of a job.
+type jobRun struct {
+	cancel context.CancelCauseFunc
+}
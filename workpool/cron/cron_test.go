@@ -0,0 +1,221 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+)
+
+var epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// blockingTask counts its runs, signals when it starts, and blocks until released or canceled.
+type blockingTask struct {
+	runs    atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingTask() *blockingTask {
+	return &blockingTask{started: make(chan struct{}, 10), release: make(chan struct{})}
+}
+
+func (t *blockingTask) Execute(ctx context.Context, _ any) (struct{}, error) {
+	t.runs.Add(1)
+	t.started <- struct{}{}
+	select {
+	case <-ctx.Done():
+		return struct{}{}, context.Cause(ctx)
+	case <-t.release:
+		return struct{}{}, nil
+	}
+}
+
+func newTestCron(t *testing.T) (*Cron[any], *clock.Fake, func()) {
+	t.Helper()
+
+	p := workpool.New[any](nil, 4)
+	p.Start()
+	fake := clock.NewFake(epoch)
+	c := New[any](p, Config{Clock: fake, Location: time.UTC})
+	c.Start()
+
+	return c, fake, func() {
+		c.Close()
+		p.Close()
+	}
+}
+
+// tick advances the fake clock by a second and waits for the cron to re-arm its timer.
+func tick(fake *clock.Fake) {
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	fake.WaitForTimers(1)
+}
+
+func TestCronOverlapSkip(t *testing.T) {
+	c, fake, cleanup := newTestCron(t)
+	defer cleanup()
+
+	tsk := newBlockingTask()
+	id, err := Add[any, struct{}](c, "* * * * * *", tsk, JobConfig{Overlap: OverlapSkip})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tick(fake)
+	<-tsk.started
+	tick(fake)
+	tick(fake)
+	if entries := c.Entries(); len(entries) != 1 || entries[0].ID != id || entries[0].Running != 1 {
+		t.Errorf("expected a single running entry, got %+v", entries)
+	}
+	close(tsk.release)
+
+	c.Close()
+	if runs := tsk.runs.Load(); runs != 1 {
+		t.Errorf("expected 1 run, got %d", runs)
+	}
+}
+
+func TestCronOverlapQueue(t *testing.T) {
+	c, fake, cleanup := newTestCron(t)
+	defer cleanup()
+
+	tsk := newBlockingTask()
+	if _, err := Add[any, struct{}](c, "* * * * * *", tsk, JobConfig{Overlap: OverlapQueue}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tick(fake)
+	<-tsk.started
+	tick(fake)
+	tick(fake)
+	if entries := c.Entries(); entries[0].Queued != 2 {
+		t.Errorf("expected 2 queued runs, got %+v", entries)
+	}
+
+	// The queued runs are executed one after the other.
+	close(tsk.release)
+	<-tsk.started
+	<-tsk.started
+	if runs := tsk.runs.Load(); runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+}
+
+func TestCronOverlapCancelPrevious(t *testing.T) {
+	c, fake, cleanup := newTestCron(t)
+	defer cleanup()
+
+	tsk := newBlockingTask()
+	errs := make(chan error, 1)
+	config := JobConfig{
+		Overlap: OverlapCancelPrevious,
+		OnError: func(_ JobID, err error) { errs <- err },
+	}
+	if _, err := Add[any, struct{}](c, "* * * * * *", tsk, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tick(fake)
+	<-tsk.started
+	tick(fake)
+	if err := <-errs; !errors.Is(err, safeconcurrencyerrors.ErrRunSuperseded) {
+		t.Errorf("expected ErrRunSuperseded, got %v", err)
+	}
+	<-tsk.started
+	close(tsk.release)
+}
+
+func TestCronJitter(t *testing.T) {
+	p := workpool.New[any](nil, 1)
+	defer p.Close()
+	fake := clock.NewFake(epoch)
+	c := New[any](p, Config{
+		Clock:    fake,
+		Location: time.UTC,
+		Rand:     func(n int64) int64 { return n / 2 },
+	})
+	defer c.Close()
+
+	tsk := newBlockingTask()
+	id, err := Add[any, struct{}](c, "0 * * * *", tsk, JobConfig{Jitter: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := c.Entries()
+	if expected := epoch.Add(time.Hour + 5*time.Second); !entries[0].Next.Equal(expected) {
+		t.Errorf("expected next run at %v, got %v", expected, entries[0].Next)
+	}
+
+	runs := c.NextRuns(id, 2)
+	if len(runs) != 2 || !runs[0].Equal(epoch.Add(time.Hour)) || !runs[1].Equal(epoch.Add(2*time.Hour)) {
+		t.Errorf("unexpected next runs %v", runs)
+	}
+	if c.NextRuns(id+1, 2) != nil {
+		t.Error("expected nil next runs for unknown job")
+	}
+}
+
+func TestCronRemove(t *testing.T) {
+	c, fake, cleanup := newTestCron(t)
+	defer cleanup()
+
+	tsk := newBlockingTask()
+	close(tsk.release)
+	id, err := Add[any, struct{}](c, "* * * * * *", tsk, JobConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.Remove(id) {
+		t.Error("expected job to be removed")
+	}
+	if c.Remove(id) {
+		t.Error("expected job to already be removed")
+	}
+
+	fake.Advance(time.Minute)
+	if len(c.Entries()) != 0 {
+		t.Errorf("expected no entries, got %+v", c.Entries())
+	}
+}
+
+func TestCronAddInvalid(t *testing.T) {
+	p := workpool.New[any](nil, 1)
+	defer p.Close()
+	c := New[any](p, Config{})
+	defer c.Close()
+
+	if _, err := Add[any, struct{}](c, "invalid", newBlockingTask(), JobConfig{}); err == nil {
+		t.Error("expected error for invalid expression")
+	}
+}
+
+func TestCronTimeout(t *testing.T) {
+	c, fake, cleanup := newTestCron(t)
+	defer cleanup()
+
+	tsk := newBlockingTask()
+	errs := make(chan error, 1)
+	var once sync.Once
+	config := JobConfig{
+		Timeout: time.Nanosecond,
+		OnError: func(_ JobID, err error) { once.Do(func() { errs <- err }) },
+	}
+	if _, err := Add[any, struct{}](c, "* * * * * *", tsk, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tick(fake)
+	if err := <-errs; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,342 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression, see [Parse] for the supported syntax.
+type Schedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// domStar and dowStar record if the day fields are unrestricted, which changes how they are combined.
+	domStar, dowStar bool
+	location         *time.Location
+}
+
+// Location returns the [time.Location] in which the [Schedule] is interpreted.
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Next returns the first time strictly after the provided time matching the [Schedule].
+// The time is returned in the [Schedule.Location].
+// The zero time is returned if no time matches within five years, for example for the 30th of February.
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Add(time.Second - time.Duration(after.Nanosecond()))
+	yearLimit := t.Year() + 5
+	// added is set once a field is incremented, the lower fields then start from their minimum.
+	added := false
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for !bitSet(s.month, int(t.Month())) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, s.location)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location)
+		}
+		t = t.AddDate(0, 0, 1)
+		// Midnight may not exist when daylight saving time starts, correct the hour to stay aligned with the day.
+		if t.Hour() != 0 {
+			if t.Hour() > 12 {
+				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+			} else {
+				t = t.Add(time.Duration(-t.Hour()) * time.Hour)
+			}
+		}
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for !bitSet(s.hour, t.Hour()) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, s.location)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for !bitSet(s.minute, t.Minute()) {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for !bitSet(s.second, t.Second()) {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+// NextN returns the next n times strictly after the provided time matching the [Schedule].
+// Fewer than n times are returned if the [Schedule] stops matching, see [Schedule.Next].
+func (s *Schedule) NextN(after time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for i := 0; i < n; i++ {
+		after = s.Next(after)
+		if after.IsZero() {
+			break
+		}
+		times = append(times, after)
+	}
+
+	return times
+}
+
+// dayMatches returns true if the day of month and day of week fields match the time.
+// As with standard cron, if both fields are restricted a time matches if either field matches.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := bitSet(s.dom, t.Day())
+	dowMatch := bitSet(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// bitSet returns true if the bit is set in the mask.
+func bitSet(mask uint64, bit int) bool {
+	return mask&(1<<uint(bit)) != 0
+}
+
+// fieldBounds describes the allowed values of a cron field.
+type fieldBounds struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondBounds = fieldBounds{name: "second", min: 0, max: 59}
+	minuteBounds = fieldBounds{name: "minute", min: 0, max: 59}
+	hourBounds   = fieldBounds{name: "hour", min: 0, max: 23}
+	domBounds    = fieldBounds{name: "day of month", min: 1, max: 31}
+	monthBounds  = fieldBounds{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Sunday may be written as either 0 or 7.
+	dowBounds = fieldBounds{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors are the predefined schedules which may be used in place of the fields.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// Parse parses a cron expression interpreted in the [time.Local] location.
+// It is equivalent to calling [ParseInLocation] with [time.Local].
+func Parse(spec string) (*Schedule, error) {
+	return ParseInLocation(spec, time.Local)
+}
+
+// ParseInLocation parses a cron expression interpreted in the provided [time.Location], unless the expression sets
+// its own time zone.
+//
+// # Syntax
+//
+// Both the standard five field syntax (minute, hour, day of month, month, day of week) and the six field syntax with a
+// leading seconds field are accepted.
+// Each field may be a wildcard "*" (or "?"), a value, a range "a-b", and any of these followed by a step "/n", or a
+// comma separated list of the above.
+// Months and days of week may be given by their three letter English names, case insensitive.
+// Sunday is both 0 and 7.
+// As with standard cron, when both day of month and day of week are restricted, a day matching either field matches.
+//
+// The descriptors @yearly (or @annually), @monthly, @weekly, @daily (or @midnight), and @hourly may be used in place of
+// the fields.
+//
+// The expression may be prefixed by "CRON_TZ=<zone> " or "TZ=<zone> " to interpret it in the named IANA time zone.
+func ParseInLocation(spec string, location *time.Location) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		zone, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(zone, "=")
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("cron: invalid time zone %q: %w", name, err)
+		}
+		location = loc
+		spec = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := descriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("cron: unknown descriptor %q", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron: expected 5 or 6 fields, got %d in %q", len(fields), spec)
+	}
+
+	schedule := &Schedule{location: location}
+	var err error
+	if schedule.second, _, err = parseField(fields[0], secondBounds); err != nil {
+		return nil, err
+	}
+	if schedule.minute, _, err = parseField(fields[1], minuteBounds); err != nil {
+		return nil, err
+	}
+	if schedule.hour, _, err = parseField(fields[2], hourBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dom, schedule.domStar, err = parseField(fields[3], domBounds); err != nil {
+		return nil, err
+	}
+	if schedule.month, _, err = parseField(fields[4], monthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dow, schedule.dowStar, err = parseField(fields[5], dowBounds); err != nil {
+		return nil, err
+	}
+	// Fold day of week 7 onto Sunday.
+	if bitSet(schedule.dow, 7) {
+		schedule.dow = schedule.dow&^(1<<7) | 1
+	}
+
+	return schedule, nil
+}
+
+// MustParse is like [Parse] but panics if the expression cannot be parsed.
+// It simplifies the initialization of global variables holding schedules.
+func MustParse(spec string) *Schedule {
+	schedule, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return schedule
+}
+
+// parseField parses a comma separated list of ranges into a bit mask.
+// It also reports if the field is an unrestricted wildcard.
+func parseField(field string, bounds fieldBounds) (uint64, bool, error) {
+	var mask uint64
+	star := false
+	for _, part := range strings.Split(field, ",") {
+		bits, partStar, err := parseRange(part, bounds)
+		if err != nil {
+			return 0, false, err
+		}
+		mask |= bits
+		star = star || partStar
+	}
+
+	return mask, star, nil
+}
+
+// parseRange parses a single wildcard, value, or range with an optional step into a bit mask.
+func parseRange(part string, bounds fieldBounds) (uint64, bool, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+	step := 1
+	if hasStep {
+		var err error
+		if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+			return 0, false, fmt.Errorf("cron: invalid step %q in %s field", stepPart, bounds.name)
+		}
+	}
+
+	var low, high int
+	star := false
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		low, high = bounds.min, bounds.max
+		star = !hasStep
+	case strings.Contains(rangePart, "-"):
+		lowPart, highPart, _ := strings.Cut(rangePart, "-")
+		var err error
+		if low, err = parseValue(lowPart, bounds); err != nil {
+			return 0, false, err
+		}
+		if high, err = parseValue(highPart, bounds); err != nil {
+			return 0, false, err
+		}
+	default:
+		var err error
+		if low, err = parseValue(rangePart, bounds); err != nil {
+			return 0, false, err
+		}
+		high = low
+		// "a/n" means every n starting at a.
+		if hasStep {
+			high = bounds.max
+		}
+	}
+
+	if low > high {
+		return 0, false, fmt.Errorf("cron: invalid range %q in %s field", rangePart, bounds.name)
+	}
+
+	var mask uint64
+	for value := low; value <= high; value += step {
+		mask |= 1 << uint(value)
+	}
+
+	return mask, star, nil
+}
+
+// parseValue parses a single numeric or named value within the bounds of the field.
+func parseValue(value string, bounds fieldBounds) (int, error) {
+	if named, ok := bounds.names[strings.ToLower(value)]; ok {
+		return named, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("cron: invalid value %q in %s field", value, bounds.name)
+	}
+	if parsed < bounds.min || parsed > bounds.max {
+		return 0, fmt.Errorf("cron: value %d out of range [%d, %d] in %s field", parsed, bounds.min, bounds.max, bounds.name)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,134 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	from := time.Date(2025, time.March, 14, 15, 9, 26, 500, time.UTC)
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.March, 14, 15, 10, 0, 0, time.UTC)},
+		{"* * * * * *", time.Date(2025, time.March, 14, 15, 9, 27, 0, time.UTC)},
+		{"*/15 * * * * *", time.Date(2025, time.March, 14, 15, 9, 30, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2025, time.March, 15, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * mon-fri", time.Date(2025, time.March, 17, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2025, time.March, 16, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches.
+		{"0 0 20 * fri", time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC)},
+		{"10/20 * * * *", time.Date(2025, time.March, 14, 15, 10, 0, 0, time.UTC)},
+		{"0 0 * DEC ?", time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.March, 14, 16, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseInLocation(test.spec, time.UTC)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.spec, err)
+
+			continue
+		}
+		if next := schedule.Next(from); !next.Equal(test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.spec, test.expected, next)
+		}
+	}
+}
+
+func TestScheduleTimeZone(t *testing.T) {
+	schedule, err := Parse("CRON_TZ=America/New_York 0 9 * * *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if schedule.Location().String() != "America/New_York" {
+		t.Errorf("expected America/New_York, got %v", schedule.Location())
+	}
+
+	from := time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)
+	// 09:00 EDT is 13:00 UTC.
+	expected := time.Date(2025, time.March, 14, 13, 0, 0, 0, time.UTC)
+	if next := schedule.Next(from); !next.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, next)
+	}
+
+	if _, err := Parse("TZ=Nowhere/Special 0 9 * * *"); err == nil {
+		t.Error("expected error for unknown time zone")
+	}
+}
+
+func TestScheduleDaylightSaving(t *testing.T) {
+	schedule, err := Parse("TZ=America/New_York 30 2 * * *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loc := schedule.Location()
+
+	// 02:30 does not exist on the 9th of March 2025 in New York, the run is skipped to the next day.
+	from := time.Date(2025, time.March, 8, 3, 0, 0, 0, loc)
+	expected := time.Date(2025, time.March, 10, 2, 30, 0, 0, loc)
+	if next := schedule.Next(from); !next.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, next)
+	}
+}
+
+func TestScheduleNextN(t *testing.T) {
+	schedule := MustParse("TZ=UTC 0 */6 * * *")
+	from := time.Date(2025, time.March, 14, 1, 0, 0, 0, time.UTC)
+	times := schedule.NextN(from, 3)
+	expected := []time.Time{
+		time.Date(2025, time.March, 14, 6, 0, 0, 0, time.UTC),
+		time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC),
+		time.Date(2025, time.March, 14, 18, 0, 0, 0, time.UTC),
+	}
+	if len(times) != len(expected) {
+		t.Fatalf("expected %d times, got %d", len(expected), len(times))
+	}
+	for i := range times {
+		if !times[i].Equal(expected[i]) {
+			t.Errorf("expected %v, got %v", expected[i], times[i])
+		}
+	}
+
+	// The 30th of February never happens.
+	if times := MustParse("0 0 30 2 *").NextN(from, 3); len(times) != 0 {
+		t.Errorf("expected no times, got %v", times)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@fortnightly",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("%q: expected error", spec)
+		}
+	}
+}
+
+func TestMustParsePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	MustParse("not a cron expression")
+}
@@ -0,0 +1,35 @@
+package workpool
+
+import "github.com/Izzette/go-safeconcurrency/api/types"
+
+// WithDeadLetters is an [Option] routing the tasks which fail in the [types.WorkerPool] to the
+// [types.DeadLetterSink], so that their errors are not lost when nobody is waiting for the result, for example when
+// tasks created with [github.com/Izzette/go-safeconcurrency/workpool/task.WrapFunc] are sent directly to
+// [types.WorkerPool.Requests].
+//
+// Only tasks implementing [types.DeadLetterTask] are routed, which is the case of all the wrappers in
+// [github.com/Izzette/go-safeconcurrency/workpool/task].
+// Tasks whose [context.Context] was canceled are not routed, nor are tasks rejected without being executed.
+// Tasks whose result is awaited by a helper returning their error, such as [Submit], are not routed either, see
+// [github.com/Izzette/go-safeconcurrency/workpool/task.Awaited].
+// Use [github.com/Izzette/go-safeconcurrency/workpool/task.Retry] to retry tasks before they are dead-lettered.
+func WithDeadLetters[ResourceT any](sink types.DeadLetterSink[ResourceT]) Option[ResourceT] {
+	return func(p *workerPool[ResourceT]) {
+		p.deadLetters = sink
+	}
+}
+
+// routeDeadLetter sends the task to the dead letter sink of the pool if it failed.
+func (p *workerPool[ResourceT]) routeDeadLetter(task types.ValuelessTask[ResourceT]) {
+	if p.deadLetters == nil {
+		return
+	}
+
+	deadLetterTask, ok := task.(types.DeadLetterTask[ResourceT])
+	if !ok {
+		return
+	}
+	if letter, failed := deadLetterTask.DeadLetter(); failed {
+		p.deadLetters.Put(letter)
+	}
+}
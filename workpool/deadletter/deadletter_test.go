@@ -0,0 +1,163 @@
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// countingTask records the tasks executed in the order they run.
+type countingTask struct {
+	id  string
+	ran chan string
+}
+
+func (t countingTask) Execute(any) {
+	t.ran <- t.id
+}
+
+// failingTask always fails.
+type failingTask struct{}
+
+func (failingTask) Execute(context.Context, any) (struct{}, error) {
+	return struct{}{}, errors.New("task error")
+}
+
+func newLetter(id string, ran chan string) types.DeadLetter[any] {
+	return types.DeadLetter[any]{
+		Task:     countingTask{id: id, ran: ran},
+		Err:      errors.New("failed " + id),
+		Attempts: 2,
+		Metadata: map[string]string{"id": id},
+	}
+}
+
+func TestMemorySinkRedrive(t *testing.T) {
+	ran := make(chan string, 3)
+	sink := NewMemorySink[any]()
+	sink.Put(newLetter("a", ran))
+	sink.Put(newLetter("b", ran))
+	if sink.Len() != 2 || len(sink.Letters()) != 2 {
+		t.Fatalf("Expected 2 letters, got %d", sink.Len())
+	}
+
+	p := workpool.New[any](nil, 1)
+	p.Start()
+	sent, err := sink.Redrive(context.Background(), p)
+	p.Close()
+	if err != nil || sent != 2 {
+		t.Errorf("Expected 2 letters sent, got %d (%v)", sent, err)
+	}
+	if first, second := <-ran, <-ran; first != "a" || second != "b" {
+		t.Errorf("Expected letters re-driven in order, got %s %s", first, second)
+	}
+	if sink.Len() != 0 {
+		t.Errorf("Expected empty sink, got %d letters", sink.Len())
+	}
+}
+
+func TestMemorySinkRedriveCanceled(t *testing.T) {
+	sink := NewMemorySink[any]()
+	sink.Put(newLetter("a", nil))
+	sink.Put(newLetter("b", nil))
+
+	// The pool is never started, so nothing can be sent.
+	p := workpool.New[any](nil, 1)
+	defer p.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sent, err := sink.Redrive(ctx, p)
+	if !errors.Is(err, context.Canceled) || sent != 0 {
+		t.Errorf("Expected context.Canceled with nothing sent, got %d (%v)", sent, err)
+	}
+	if letters := sink.Take(); len(letters) != 2 || letters[0].Metadata["id"] != "a" {
+		t.Errorf("Expected the letters to be returned to the sink, got %+v", letters)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	sink, err := OpenFileSink[any](path, FileSinkConfig{Clock: fake})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sink.Put(newLetter("a", nil))
+	sink.Put(newLetter("b", nil))
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	NewFileSink[any](&buf, FileSinkConfig{Clock: fake}).Put(newLetter("c", nil))
+	expected := `{"time":"2025-01-01T00:00:00Z","error":"failed c","attempts":2,"metadata":{"id":"c"}}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	ran := make(chan string, 2)
+	decode := func(record Record) (types.ValuelessTask[any], error) {
+		return countingTask{id: record.Metadata["id"], ran: ran}, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer file.Close()
+
+	letters, err := ReadLetters[any](file, decode)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(letters) != 2 || letters[1].Err.Error() != "failed b" || letters[1].Attempts != 2 {
+		t.Fatalf("Unexpected letters %+v", letters)
+	}
+
+	p := workpool.New[any](nil, 1)
+	p.Start()
+	defer p.Close()
+	if sent, err := Redrive(context.Background(), p, letters); err != nil || sent != 2 {
+		t.Errorf("Expected 2 letters sent, got %d (%v)", sent, err)
+	}
+	if first, second := <-ran, <-ran; first != "a" || second != "b" {
+		t.Errorf("Expected letters re-driven in order, got %s %s", first, second)
+	}
+}
+
+func TestReadLettersErrors(t *testing.T) {
+	decode := func(Record) (types.ValuelessTask[any], error) { return nil, errors.New("unknown task") }
+	if _, err := ReadLetters[any](strings.NewReader(`{"error":"x"}`+"\n"), decode); err == nil {
+		t.Error("Expected decode error")
+	}
+	if _, err := ReadLetters[any](strings.NewReader("not json"), decode); err == nil {
+		t.Error("Expected parse error")
+	}
+}
+
+func TestPoolFileSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewFileSink[any](&buf, FileSinkConfig{})
+	p := workpool.New[any](nil, 1, workpool.WithDeadLetters[any](sink))
+	p.Start()
+
+	ctx := task.WithMetadata(context.Background(), "id", "42")
+	retried := task.Retry[any, struct{}](failingTask{}, task.RetryConfig{MaxAttempts: 3})
+	// Fire-and-forget: the error is only reported to the sink.
+	valuelessTask, _ := task.Wrap[any, struct{}](ctx, retried)
+	p.Requests() <- valuelessTask
+	p.Close()
+
+	if !strings.Contains(buf.String(), `"attempts":3,"metadata":{"id":"42"}`) {
+		t.Errorf("Unexpected record %q", buf.String())
+	}
+}
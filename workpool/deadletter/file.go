@@ -0,0 +1,126 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+)
+
+// Record is the JSON representation of a [types.DeadLetter] written by a [FileSink], one per line.
+// The task itself is not serializable, its [types.DeadLetter.Metadata] should be used to identify it.
+type Record struct {
+	Time     time.Time         `json:"time"`
+	Error    string            `json:"error"`
+	Attempts uint              `json:"attempts"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// FileSinkConfig configures a [FileSink].
+type FileSinkConfig struct {
+	// Clock is used to timestamp the records, the [clock.Real] clock is used if nil.
+	Clock types.Clock
+
+	// OnError is called with the errors encountered writing records, which are otherwise discarded.
+	OnError func(error)
+}
+
+// FileSink is a [types.DeadLetterSink] writing the dead letters as JSON lines, see [Record].
+// It is safe for concurrent use.
+type FileSink[ResourceT any] struct {
+	clock   types.Clock
+	onError func(error)
+
+	// mu protects encoder, and serializes the writes.
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewFileSink creates a [FileSink] writing to the [io.Writer].
+// Each record is written with a single call to [io.Writer.Write].
+func NewFileSink[ResourceT any](w io.Writer, config FileSinkConfig) *FileSink[ResourceT] {
+	return &FileSink[ResourceT]{
+		clock:   clock.OrReal(config.Clock),
+		onError: config.OnError,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// OpenFileSink creates a [FileSink] appending to the file at the path, creating it if needed.
+// The file is closed by [FileSink.Close].
+func OpenFileSink[ResourceT any](path string, config FileSinkConfig) (*FileSink[ResourceT], error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening dead letter file: %w", err)
+	}
+
+	sink := NewFileSink[ResourceT](file, config)
+	sink.closer = file
+
+	return sink, nil
+}
+
+// Put implements [types.DeadLetterSink.Put].
+func (s *FileSink[ResourceT]) Put(letter types.DeadLetter[ResourceT]) {
+	record := Record{
+		Time:     s.clock.Now(),
+		Error:    letter.Err.Error(),
+		Attempts: letter.Attempts,
+		Metadata: letter.Metadata,
+	}
+
+	s.mu.Lock()
+	err := s.encoder.Encode(record)
+	s.mu.Unlock()
+
+	if err != nil && s.onError != nil {
+		s.onError(fmt.Errorf("writing dead letter: %w", err))
+	}
+}
+
+// Close closes the file opened by [OpenFileSink], it does nothing for sinks created by [NewFileSink].
+func (s *FileSink[ResourceT]) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+
+	//nolint:wrapcheck
+	return s.closer.Close()
+}
+
+// ReadLetters reads the records written by a [FileSink], and rebuilds the dead letters using the decode function to
+// recreate their tasks, so that they may be re-driven with [Redrive].
+// The [types.DeadLetter.Err] of the rebuilt letters only preserves the message of the original error.
+func ReadLetters[ResourceT any](
+	r io.Reader,
+	decode func(Record) (types.ValuelessTask[ResourceT], error),
+) ([]types.DeadLetter[ResourceT], error) {
+	decoder := json.NewDecoder(r)
+	var letters []types.DeadLetter[ResourceT]
+	for {
+		var record Record
+		if err := decoder.Decode(&record); errors.Is(err, io.EOF) {
+			return letters, nil
+		} else if err != nil {
+			return letters, fmt.Errorf("reading dead letter: %w", err)
+		}
+
+		task, err := decode(record)
+		if err != nil {
+			return letters, fmt.Errorf("decoding dead letter: %w", err)
+		}
+		letters = append(letters, types.DeadLetter[ResourceT]{
+			Task:     task,
+			Err:      errors.New(record.Error),
+			Attempts: record.Attempts,
+			Metadata: record.Metadata,
+		})
+	}
+}
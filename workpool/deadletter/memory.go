@@ -0,0 +1,69 @@
+package deadletter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// MemorySink is a [types.DeadLetterSink] keeping the dead letters in memory.
+// It is safe for concurrent use.
+type MemorySink[ResourceT any] struct {
+	mu      sync.Mutex
+	letters []types.DeadLetter[ResourceT]
+}
+
+// NewMemorySink creates an empty [MemorySink].
+func NewMemorySink[ResourceT any]() *MemorySink[ResourceT] {
+	return &MemorySink[ResourceT]{}
+}
+
+// Put implements [types.DeadLetterSink.Put].
+func (s *MemorySink[ResourceT]) Put(letter types.DeadLetter[ResourceT]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters = append(s.letters, letter)
+}
+
+// Len returns the number of dead letters in the sink.
+func (s *MemorySink[ResourceT]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.letters)
+}
+
+// Letters returns a copy of the dead letters in the sink, in the order they were received.
+func (s *MemorySink[ResourceT]) Letters() []types.DeadLetter[ResourceT] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]types.DeadLetter[ResourceT](nil), s.letters...)
+}
+
+// Take removes and returns the dead letters in the sink, in the order they were received.
+func (s *MemorySink[ResourceT]) Take() []types.DeadLetter[ResourceT] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letters := s.letters
+	s.letters = nil
+
+	return letters
+}
+
+// Redrive takes the dead letters in the sink and sends their tasks to the [types.WorkerPool], see [Redrive].
+// The letters which could not be sent are returned to the sink.
+func (s *MemorySink[ResourceT]) Redrive(ctx context.Context, pool types.WorkerPool[ResourceT]) (int, error) {
+	letters := s.Take()
+	sent, err := Redrive(ctx, pool, letters)
+	if sent < len(letters) {
+		s.mu.Lock()
+		s.letters = append(letters[sent:len(letters):len(letters)], s.letters...)
+		s.mu.Unlock()
+	}
+
+	return sent, err
+}
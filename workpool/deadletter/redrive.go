@@ -0,0 +1,35 @@
+package deadletter
+
+import (
+	"context"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Redrive sends the tasks of the dead letters to the [types.WorkerPool], in order, without waiting for their results.
+// If the pool is configured with [github.com/Izzette/go-safeconcurrency/workpool.WithDeadLetters], tasks which fail
+// again are dead-lettered again.
+// It returns the number of letters sent, and the [context.Cause] if the [context.Context] is canceled before all the
+// letters are sent.
+func Redrive[ResourceT any](
+	ctx context.Context,
+	pool types.WorkerPool[ResourceT],
+	letters []types.DeadLetter[ResourceT],
+) (int, error) {
+	for i, letter := range letters {
+		// select is not deterministic, and may still send tasks even if the context has been canceled.
+		if err := context.Cause(ctx); err != nil {
+			//nolint:wrapcheck
+			return i, err
+		}
+
+		select {
+		case <-ctx.Done():
+			//nolint:wrapcheck
+			return i, context.Cause(ctx)
+		case pool.Requests() <- letter.Task:
+		}
+	}
+
+	return len(letters), nil
+}
@@ -0,0 +1,65 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// channelSink is a [types.DeadLetterSink] publishing the dead letters to a channel.
+type channelSink chan types.DeadLetter[any]
+
+func (s channelSink) Put(letter types.DeadLetter[any]) {
+	s <- letter
+}
+
+func TestPoolDeadLetters(t *testing.T) {
+	sink := make(channelSink, 1)
+	p := New[any](nil, 1, WithDeadLetters[any](sink))
+	p.Start()
+	defer p.Close()
+
+	taskErr := errors.New("task error")
+	ctx := task.WithMetadata(context.Background(), "id", "42")
+	// Fire-and-forget: nobody waits for the result.
+	bareTask, _ := task.WrapFunc[any](ctx, func(context.Context, any) error { return taskErr })
+	p.Requests() <- bareTask
+
+	letter := <-sink
+	if !errors.Is(letter.Err, taskErr) || letter.Attempts != 1 || letter.Metadata["id"] != "42" {
+		t.Errorf("Unexpected dead letter %+v", letter)
+	}
+
+	// Successful tasks are not dead-lettered, and the re-driven task fails again.
+	if err := SubmitFunc[any](context.Background(), p, func(context.Context, any) error { return nil }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.Requests() <- letter.Task
+	if letter := <-sink; !errors.Is(letter.Err, taskErr) {
+		t.Errorf("Expected the re-driven task to be dead-lettered, got %+v", letter)
+	}
+}
+
+func TestPoolDeadLettersAwaited(t *testing.T) {
+	sink := make(channelSink, 2)
+	p := New[any](nil, 1, WithDeadLetters[any](sink))
+	p.Start()
+
+	// The errors of the awaited tasks are returned to the caller, and must not be dead-lettered as well.
+	taskErr := errors.New("task error")
+	retried := task.Retry[any, int](&mockTask{err: taskErr}, task.RetryConfig{MaxAttempts: 2})
+	if _, err := Submit[any, int](context.Background(), p, retried); !errors.Is(err, taskErr) {
+		t.Errorf("Expected %v, got %v", taskErr, err)
+	}
+	if err := SubmitFunc[any](context.Background(), p, func(context.Context, any) error { return taskErr }); err == nil {
+		t.Error("Expected error")
+	}
+	p.Close()
+
+	if len(sink) != 0 {
+		t.Errorf("Expected no dead letter, got %+v", <-sink)
+	}
+}
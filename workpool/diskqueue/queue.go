@@ -0,0 +1,681 @@
+package diskqueue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool/registry"
+)
+
+// SyncPolicy selects when the appended records are flushed to disk with [os.File.Sync].
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes each record before [Queue.Enqueue] returns, no acknowledged enqueue is lost on crash.
+	SyncAlways SyncPolicy = iota
+
+	// SyncInterval flushes the records in the background every [Config.SyncInterval], the records appended during the
+	// last interval may be lost on crash.
+	SyncInterval
+
+	// SyncNever leaves flushing to the operating system, except when a segment is rotated or the queue is closed.
+	SyncNever
+)
+
+const (
+	// DefaultSegmentSize is the default [Config.SegmentSize].
+	DefaultSegmentSize = 64 << 20
+
+	// DefaultSyncInterval is the default [Config.SyncInterval].
+	DefaultSyncInterval = time.Second
+)
+
+// Config configures a [Queue].
+type Config[ResourceT any, ValueT any] struct {
+	// Dir is the directory holding the segments of the queue, it is created if needed.
+	// A directory must not be used by more than one [Queue] at a time.
+	Dir string
+
+	// Registry is used to serialize the tasks, their types must be registered.
+	Registry *registry.Registry[ResourceT, ValueT]
+
+	// Sync selects when the records are flushed to disk, [SyncAlways] by default.
+	Sync SyncPolicy
+
+	// SyncInterval is the interval used by [SyncInterval], [DefaultSyncInterval] if 0.
+	SyncInterval time.Duration
+
+	// SegmentSize is the size after which a new segment is started, [DefaultSegmentSize] if 0.
+	SegmentSize int64
+
+	// Clock is used by [SyncInterval], the [clock.Real] clock is used if nil.
+	Clock types.Clock
+
+	// OnError is called with the errors of the delivered tasks, and the errors encountered acknowledging them.
+	OnError func(error)
+}
+
+// Queue is a durable task queue backed by an append-only log on local disk, so that queued tasks survive restarts.
+// Tasks added with [Queue.Enqueue] are delivered to a [types.WorkerPool] by [Queue.Run], and acknowledged once they
+// execute successfully.
+//
+// # Delivery
+//
+// Delivery is at-least-once: a task is acknowledged after it succeeds, and every task which is not acknowledged when
+// the queue is opened again is replayed, in the order it was enqueued.
+// Tasks which fail, or which are rejected by the pool, are not acknowledged and are only delivered again once the
+// queue is re-opened.
+// Use [github.com/Izzette/go-safeconcurrency/workpool/task.Retry] to retry tasks before giving up.
+// Tasks must therefore be idempotent.
+//
+// # Segments
+//
+// The log is split in segments of about [Config.SegmentSize].
+// Segments are removed once all their tasks are acknowledged, and all the older segments have been removed.
+// Use [Queue.Compact] to rewrite the tasks which are not acknowledged and remove all the older segments.
+type Queue[ResourceT any, ValueT any] struct {
+	dir          string
+	registry     *registry.Registry[ResourceT, ValueT]
+	syncPolicy   SyncPolicy
+	syncInterval time.Duration
+	segmentSize  int64
+	clock        types.Clock
+	onError      func(error)
+
+	// mu protects all the fields below, and serializes the writes to the log.
+	mu         sync.Mutex
+	closed     bool
+	segments   []*segment
+	active     *os.File
+	activeSize int64
+	dirty      bool
+	nextID     uint64
+	pending    map[uint64]*pendingTask
+	// ready lists the pending tasks which have not been delivered yet, in the order they were enqueued.
+	ready []uint64
+	// broken is the error of the compaction which left the segments inconsistent with the state of the queue.
+	broken error
+
+	// notify is signaled when a task is enqueued.
+	notify   chan struct{}
+	done     chan struct{}
+	syncDone chan struct{}
+}
+
+// pendingTask is a task which is not acknowledged yet.
+type pendingTask struct {
+	segment  *segment
+	envelope registry.Envelope
+}
+
+// Open opens the queue in the [Config.Dir], replaying the tasks which were not acknowledged.
+// A partially written record at the end of the last segment, as left by a crash, is discarded.
+// Other corruption is reported with an error wrapping [safeconcurrencyerrors.ErrCorruptSegment].
+func Open[ResourceT any, ValueT any](config Config[ResourceT, ValueT]) (*Queue[ResourceT, ValueT], error) {
+	q := &Queue[ResourceT, ValueT]{
+		dir:          config.Dir,
+		registry:     config.Registry,
+		syncPolicy:   config.Sync,
+		syncInterval: config.SyncInterval,
+		segmentSize:  config.SegmentSize,
+		clock:        clock.OrReal(config.Clock),
+		onError:      config.OnError,
+		nextID:       1,
+		pending:      make(map[uint64]*pendingTask),
+		notify:       make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		syncDone:     make(chan struct{}),
+	}
+	if q.syncInterval <= 0 {
+		q.syncInterval = DefaultSyncInterval
+	}
+	if q.segmentSize <= 0 {
+		q.segmentSize = DefaultSegmentSize
+	}
+
+	if err := os.MkdirAll(q.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating queue directory: %w", err)
+	}
+	if err := q.replay(); err != nil {
+		return nil, err
+	}
+	if err := q.deleteAcknowledgedPrefix(); err != nil {
+		q.active.Close()
+
+		return nil, err
+	}
+
+	if q.syncPolicy == SyncInterval {
+		go q.syncLoop()
+	} else {
+		close(q.syncDone)
+	}
+
+	return q, nil
+}
+
+// replay reads the segments of the log, and opens the last one for appending.
+func (q *Queue[ResourceT, ValueT]) replay() error {
+	indexes, err := listSegments(q.dir)
+	if err != nil {
+		return err
+	}
+
+	var activeSize int64
+	for i, index := range indexes {
+		seg := &segment{index: index}
+		valid, torn, err := readSegment(segmentPath(q.dir, index), func(rec record) {
+			q.apply(seg, rec)
+		})
+		if err != nil {
+			return err
+		}
+		if torn && i != len(indexes)-1 {
+			return fmt.Errorf("%w: %s", safeconcurrencyerrors.ErrCorruptSegment, segmentPath(q.dir, index))
+		}
+		q.segments = append(q.segments, seg)
+		activeSize = valid
+	}
+
+	q.ready = make([]uint64, 0, len(q.pending))
+	for id := range q.pending {
+		q.ready = append(q.ready, id)
+	}
+	sort.Slice(q.ready, func(i, j int) bool { return q.ready[i] < q.ready[j] })
+
+	if len(q.segments) == 0 {
+		return q.createSegment(1)
+	}
+
+	// Appends continue after the last valid record of the last segment.
+	path := segmentPath(q.dir, q.segments[len(q.segments)-1].index)
+	if err := os.Truncate(path, activeSize); err != nil {
+		return fmt.Errorf("truncating queue segment: %w", err)
+	}
+	active, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening queue segment: %w", err)
+	}
+	q.active = active
+	q.activeSize = activeSize
+
+	return nil
+}
+
+// apply applies the record read from the segment to the state of the queue.
+func (q *Queue[ResourceT, ValueT]) apply(seg *segment, rec record) {
+	if rec.ID >= q.nextID {
+		q.nextID = rec.ID + 1
+	}
+
+	switch rec.Op {
+	case opEnqueue:
+		if rec.Task == nil {
+			return
+		}
+		if previous, ok := q.pending[rec.ID]; ok {
+			// The task was rewritten by an interrupted compaction.
+			previous.segment.live--
+		}
+		q.pending[rec.ID] = &pendingTask{segment: seg, envelope: *rec.Task}
+		seg.live++
+	case opAck:
+		if task, ok := q.pending[rec.ID]; ok {
+			task.segment.live--
+			delete(q.pending, rec.ID)
+		}
+	}
+}
+
+// Enqueue serializes the [types.Task] using the [Config.Registry] and appends it to the log.
+// It returns the identifier of the task once it is durable according to the [SyncPolicy].
+func (q *Queue[ResourceT, ValueT]) Enqueue(task types.Task[ResourceT, ValueT]) (uint64, error) {
+	envelope, err := q.registry.Encode(task)
+	if err != nil {
+		//nolint:wrapcheck
+		return 0, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.usable(); err != nil {
+		return 0, err
+	}
+
+	id := q.nextID
+	if err := q.append(record{Op: opEnqueue, ID: id, Task: &envelope}); err != nil {
+		return 0, err
+	}
+	q.nextID++
+	seg := q.segments[len(q.segments)-1]
+	seg.live++
+	q.pending[id] = &pendingTask{segment: seg, envelope: envelope}
+	q.ready = append(q.ready, id)
+	q.signal()
+
+	if err := q.maybeRotate(); err != nil {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// Pending returns the number of tasks which are not acknowledged yet, including tasks being executed.
+func (q *Queue[ResourceT, ValueT]) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// Run delivers the tasks of the queue to the [types.WorkerPool] in the order they were enqueued, starting with the
+// tasks replayed by [Open], and waits for new tasks until the [context.Context] is canceled or the queue is closed.
+// The tasks are executed with the context, and acknowledged once they succeed.
+// It returns the [context.Cause] if the context is canceled, or nil if the queue is closed.
+func (q *Queue[ResourceT, ValueT]) Run(ctx context.Context, pool types.WorkerPool[ResourceT]) error {
+	for {
+		if err := context.Cause(ctx); err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+
+		id, envelope, ok := q.next()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				//nolint:wrapcheck
+				return context.Cause(ctx)
+			case <-q.done:
+				return nil
+			case <-q.notify:
+				continue
+			}
+		}
+
+		task, err := q.registry.Decode(envelope)
+		if err != nil {
+			q.reportError(fmt.Errorf("decoding task %d: %w", id, err))
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			q.requeue(id)
+
+			//nolint:wrapcheck
+			return context.Cause(ctx)
+		case <-q.done:
+			return nil
+		case pool.Requests() <- &delivery[ResourceT, ValueT]{ctx: ctx, queue: q, id: id, task: task}:
+		}
+	}
+}
+
+// next pops the next task to deliver.
+func (q *Queue[ResourceT, ValueT]) next() (uint64, registry.Envelope, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.ready) > 0 {
+		id := q.ready[0]
+		q.ready = q.ready[1:]
+		if task, ok := q.pending[id]; ok {
+			return id, task.envelope, true
+		}
+	}
+
+	return 0, registry.Envelope{}, false
+}
+
+// requeue puts back the task at the front of the tasks to deliver.
+func (q *Queue[ResourceT, ValueT]) requeue(id uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.ready = append([]uint64{id}, q.ready...)
+}
+
+// ack appends an acknowledgement for the task.
+func (q *Queue[ResourceT, ValueT]) ack(id uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.usable(); err != nil {
+		return err
+	}
+	task, ok := q.pending[id]
+	if !ok {
+		return nil
+	}
+
+	if err := q.append(record{Op: opAck, ID: id}); err != nil {
+		return err
+	}
+	delete(q.pending, id)
+	task.segment.live--
+
+	if err := q.deleteAcknowledgedPrefix(); err != nil {
+		return err
+	}
+
+	return q.maybeRotate()
+}
+
+// Compact rewrites the tasks which are not acknowledged to a new segment, and removes all the older segments.
+// It blocks the other operations of the queue while it runs.
+//
+// If Compact fails once the new segment is in place, but before the queue switched to it, the queue is broken: the
+// later operations fail with [safeconcurrencyerrors.ErrQueueBroken], and the queue must be closed and re-opened.
+func (q *Queue[ResourceT, ValueT]) Compact() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.usable(); err != nil {
+		return err
+	}
+
+	last := q.segments[len(q.segments)-1].index
+	if err := q.active.Sync(); err != nil {
+		return fmt.Errorf("syncing queue segment: %w", err)
+	}
+
+	compacted := &segment{index: last + 1}
+	if err := q.writeCompacted(compacted); err != nil {
+		return err
+	}
+	// The compacted segment is replayed after the active segment, which must therefore not be appended to anymore.
+	active, err := q.openSegment(last + 2)
+	if err != nil {
+		q.broken = err
+
+		return err
+	}
+
+	// The state of the queue is only updated once the compacted segment and the new active segment are durable.
+	previous := q.active
+	older := q.segments
+	for _, task := range q.pending {
+		task.segment = compacted
+	}
+	compacted.live = len(q.pending)
+	q.segments = []*segment{compacted, {index: last + 2}}
+	q.active = active
+	q.activeSize = 0
+	q.dirty = false
+
+	if err := previous.Close(); err != nil {
+		return fmt.Errorf("closing queue segment: %w", err)
+	}
+	// The older segments left behind if this fails are replayed before the compacted segment, and removed once the
+	// queue is re-opened.
+	for _, seg := range older {
+		if err := os.Remove(segmentPath(q.dir, seg.index)); err != nil {
+			return fmt.Errorf("removing queue segment: %w", err)
+		}
+	}
+	if err := syncDir(q.dir); err != nil {
+		return err
+	}
+
+	return q.deleteAcknowledgedPrefix()
+}
+
+// writeCompacted writes the pending tasks to the compacted segment.
+// The rename of the compacted segment is only durable once the directory is synced.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) writeCompacted(compacted *segment) error {
+	ids := make([]uint64, 0, len(q.pending))
+	for id := range q.pending {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	path := segmentPath(q.dir, compacted.index)
+	file, err := os.OpenFile(path+tempSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating compacted segment: %w", err)
+	}
+	defer file.Close()
+
+	for _, id := range ids {
+		envelope := q.pending[id].envelope
+		buf, err := encodeRecord(record{Op: opEnqueue, ID: id, Task: &envelope})
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(buf); err != nil {
+			return fmt.Errorf("writing compacted segment: %w", err)
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("syncing compacted segment: %w", err)
+	}
+	if err := os.Rename(path+tempSuffix, path); err != nil {
+		return fmt.Errorf("renaming compacted segment: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops [Queue.Run], flushes the log to disk, and closes it.
+// Tasks executing when the queue is closed are not acknowledged, and will be delivered again once the queue is
+// re-opened.
+// It is safe to call Close multiple times.
+func (q *Queue[ResourceT, ValueT]) Close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+
+		return nil
+	}
+	q.closed = true
+	close(q.done)
+	q.mu.Unlock()
+
+	<-q.syncDone
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.active.Sync(); err != nil {
+		q.active.Close()
+
+		return fmt.Errorf("syncing queue segment: %w", err)
+	}
+	if err := q.active.Close(); err != nil {
+		return fmt.Errorf("closing queue segment: %w", err)
+	}
+
+	return nil
+}
+
+// append writes the record to the active segment, and flushes it according to the [SyncPolicy].
+// A partially written record is truncated so that the following records remain readable.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) append(rec record) error {
+	buf, err := encodeRecord(rec)
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.active.Write(buf); err != nil {
+		_ = q.active.Truncate(q.activeSize)
+
+		return fmt.Errorf("writing queue record: %w", err)
+	}
+	q.activeSize += int64(len(buf))
+
+	if q.syncPolicy != SyncAlways {
+		q.dirty = true
+
+		return nil
+	}
+	if err := q.active.Sync(); err != nil {
+		return fmt.Errorf("syncing queue segment: %w", err)
+	}
+
+	return nil
+}
+
+// maybeRotate starts a new segment if the active segment is full.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) maybeRotate() error {
+	if q.activeSize < q.segmentSize {
+		return nil
+	}
+
+	if err := q.active.Sync(); err != nil {
+		return fmt.Errorf("syncing queue segment: %w", err)
+	}
+	if err := q.active.Close(); err != nil {
+		return fmt.Errorf("closing queue segment: %w", err)
+	}
+
+	if err := q.createSegment(q.segments[len(q.segments)-1].index + 1); err != nil {
+		return err
+	}
+
+	return q.deleteAcknowledgedPrefix()
+}
+
+// createSegment creates a new active segment with the index.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) createSegment(index uint64) error {
+	active, err := q.openSegment(index)
+	if err != nil {
+		return err
+	}
+	q.active = active
+	q.activeSize = 0
+	q.dirty = false
+	q.segments = append(q.segments, &segment{index: index})
+
+	return nil
+}
+
+// openSegment creates the segment with the index, and syncs the directory so that it is durable.
+func (q *Queue[ResourceT, ValueT]) openSegment(index uint64) (*os.File, error) {
+	file, err := os.OpenFile(segmentPath(q.dir, index), os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("creating queue segment: %w", err)
+	}
+	if err := syncDir(q.dir); err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// usable returns an error if the queue is closed, or broken by a failed compaction.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) usable() error {
+	if q.closed {
+		return safeconcurrencyerrors.ErrQueueClosed
+	}
+	if q.broken != nil {
+		return fmt.Errorf("%w: %w", safeconcurrencyerrors.ErrQueueBroken, q.broken)
+	}
+
+	return nil
+}
+
+// deleteAcknowledgedPrefix removes the oldest segments, as long as all their tasks are acknowledged.
+// Acknowledgements are always appended after the task they acknowledge, so the removed segments never acknowledge
+// tasks of the remaining segments.
+// The active segment is never removed.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) deleteAcknowledgedPrefix() error {
+	removed := false
+	for len(q.segments) > 1 && q.segments[0].live == 0 {
+		if err := os.Remove(segmentPath(q.dir, q.segments[0].index)); err != nil {
+			return fmt.Errorf("removing queue segment: %w", err)
+		}
+		q.segments = q.segments[1:]
+		removed = true
+	}
+	if !removed {
+		return nil
+	}
+
+	return syncDir(q.dir)
+}
+
+// syncLoop flushes the log periodically for [SyncInterval].
+func (q *Queue[ResourceT, ValueT]) syncLoop() {
+	defer close(q.syncDone)
+
+	timer := q.clock.NewTimer(q.syncInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-q.done:
+			return
+		case <-timer.C():
+		}
+
+		q.mu.Lock()
+		if q.dirty && !q.closed {
+			q.dirty = false
+			if err := q.active.Sync(); err != nil {
+				q.reportError(fmt.Errorf("syncing queue segment: %w", err))
+			}
+		}
+		q.mu.Unlock()
+		timer.Reset(q.syncInterval)
+	}
+}
+
+// signal wakes [Queue.Run] without blocking.
+func (q *Queue[ResourceT, ValueT]) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// reportError calls [Config.OnError] if set.
+func (q *Queue[ResourceT, ValueT]) reportError(err error) {
+	if q.onError != nil {
+		q.onError(err)
+	}
+}
+
+// delivery is a task delivered to the [types.WorkerPool] by [Queue.Run].
+type delivery[ResourceT any, ValueT any] struct {
+	//nolint:containedctx
+	ctx   context.Context
+	queue *Queue[ResourceT, ValueT]
+	id    uint64
+	task  types.Task[ResourceT, ValueT]
+}
+
+// Execute implements [types.ValuelessTask.Execute].
+func (d *delivery[ResourceT, ValueT]) Execute(resource ResourceT) {
+	if _, err := d.task.Execute(d.ctx, resource); err != nil {
+		d.queue.reportError(fmt.Errorf("task %d failed: %w", d.id, err))
+
+		return
+	}
+
+	if err := d.queue.ack(d.id); err != nil {
+		d.queue.reportError(fmt.Errorf("acknowledging task %d: %w", d.id, err))
+	}
+}
+
+// Reject implements [types.RejectableTask.Reject].
+// The task is not acknowledged, and will be delivered again once the queue is re-opened.
+func (d *delivery[ResourceT, ValueT]) Reject(err error) {
+	d.queue.reportError(fmt.Errorf("task %d rejected: %w", d.id, err))
+}
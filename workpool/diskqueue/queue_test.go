@@ -0,0 +1,319 @@
+package diskqueue
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+	"github.com/Izzette/go-safeconcurrency/workpool/registry"
+)
+
+// recorder is the pool resource, it records the names of the executed tasks.
+type recorder struct {
+	ran chan string
+}
+
+// recordTask records its name, and fails if requested.
+type recordTask struct {
+	Name string `json:"name"`
+	Fail bool   `json:"fail"`
+}
+
+func (t *recordTask) Execute(_ context.Context, r *recorder) (struct{}, error) {
+	r.ran <- t.Name
+	if t.Fail {
+		return struct{}{}, errors.New("task failed")
+	}
+
+	return struct{}{}, nil
+}
+
+func newTestRegistry() *registry.Registry[*recorder, struct{}] {
+	r := registry.New[*recorder, struct{}]()
+	registry.Register[*recordTask](r, "record", registry.JSON)
+
+	return r
+}
+
+// tempDir returns a temporary directory for the queue, skipping the test in short mode as the disk is slow.
+func tempDir(t *testing.T) string {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping disk-backed queue test in short mode")
+	}
+
+	return t.TempDir()
+}
+
+func openQueue(t *testing.T, dir string, segmentSize int64) *Queue[*recorder, struct{}] {
+	t.Helper()
+
+	q, err := Open(Config[*recorder, struct{}]{Dir: dir, Registry: newTestRegistry(), SegmentSize: segmentSize})
+	if err != nil {
+		t.Fatalf("Unexpected error opening queue: %v", err)
+	}
+
+	return q
+}
+
+func enqueue(t *testing.T, q *Queue[*recorder, struct{}], tasks ...*recordTask) {
+	t.Helper()
+
+	for _, tsk := range tasks {
+		if _, err := q.Enqueue(tsk); err != nil {
+			t.Fatalf("Unexpected error enqueuing task: %v", err)
+		}
+	}
+}
+
+// runUntil runs the queue until the expected tasks have executed, and waits for them to be acknowledged.
+func runUntil(t *testing.T, q *Queue[*recorder, struct{}], expected ...string) {
+	t.Helper()
+
+	r := &recorder{ran: make(chan string, len(expected))}
+	p := workpool.New[*recorder](r, 1)
+	p.Start()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- q.Run(ctx, p) }()
+	for _, name := range expected {
+		if ran := <-r.ran; ran != name {
+			t.Errorf("Expected task %q, got %q", name, ran)
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	// The workers acknowledge the tasks before exiting.
+	p.Close()
+}
+
+func countSegments(t *testing.T, dir string) int {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return len(matches)
+}
+
+func TestQueueReplay(t *testing.T) {
+	dir := tempDir(t)
+	q := openQueue(t, dir, 0)
+	enqueue(t, q, &recordTask{Name: "a"}, &recordTask{Name: "b"})
+	if err := q.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The tasks survive the restart, and are delivered in order.
+	q = openQueue(t, dir, 0)
+	if q.Pending() != 2 {
+		t.Errorf("Expected 2 pending tasks, got %d", q.Pending())
+	}
+	runUntil(t, q, "a", "b")
+	enqueue(t, q, &recordTask{Name: "c"})
+	runUntil(t, q, "c")
+	if q.Pending() != 0 {
+		t.Errorf("Expected no pending tasks, got %d", q.Pending())
+	}
+	q.Close()
+
+	q = openQueue(t, dir, 0)
+	defer q.Close()
+	if q.Pending() != 0 {
+		t.Errorf("Expected no pending tasks after restart, got %d", q.Pending())
+	}
+	// Identifiers are not reused.
+	if id, _ := q.Enqueue(&recordTask{Name: "d"}); id != 4 {
+		t.Errorf("Expected id 4, got %d", id)
+	}
+}
+
+func TestQueueFailedTasksAreReplayed(t *testing.T) {
+	dir := tempDir(t)
+	errs := make(chan error, 1)
+	q, err := Open(Config[*recorder, struct{}]{
+		Dir:      dir,
+		Registry: newTestRegistry(),
+		OnError:  func(err error) { errs <- err },
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	enqueue(t, q, &recordTask{Name: "a", Fail: true})
+	runUntil(t, q, "a")
+	if err := <-errs; err == nil {
+		t.Error("Expected the task error to be reported")
+	}
+	q.Close()
+
+	q = openQueue(t, dir, 0)
+	defer q.Close()
+	if q.Pending() != 1 {
+		t.Errorf("Expected the failed task to be pending, got %d", q.Pending())
+	}
+}
+
+func TestQueueTornTail(t *testing.T) {
+	dir := tempDir(t)
+	q := openQueue(t, dir, 0)
+	enqueue(t, q, &recordTask{Name: "a"})
+	q.Close()
+
+	// Simulate a crash in the middle of writing a record.
+	path := segmentPath(dir, 1)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	file.Write([]byte{0, 0, 0, 42, 1, 2})
+	file.Close()
+
+	q = openQueue(t, dir, 0)
+	enqueue(t, q, &recordTask{Name: "b"})
+	q.Close()
+
+	q = openQueue(t, dir, 0)
+	defer q.Close()
+	if q.Pending() != 2 {
+		t.Errorf("Expected 2 pending tasks, got %d", q.Pending())
+	}
+}
+
+func TestQueueCorruptSegment(t *testing.T) {
+	dir := tempDir(t)
+	q := openQueue(t, dir, 1)
+	enqueue(t, q, &recordTask{Name: "a"}, &recordTask{Name: "b"})
+	q.Close()
+
+	if err := os.WriteFile(segmentPath(dir, 1), []byte("garbage"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err := Open(Config[*recorder, struct{}]{Dir: dir, Registry: newTestRegistry()})
+	if !errors.Is(err, safeconcurrencyerrors.ErrCorruptSegment) {
+		t.Errorf("Expected ErrCorruptSegment, got %v", err)
+	}
+}
+
+func TestQueueRotation(t *testing.T) {
+	dir := tempDir(t)
+	// Every record starts a new segment.
+	q := openQueue(t, dir, 1)
+	defer q.Close()
+
+	enqueue(t, q, &recordTask{Name: "a"}, &recordTask{Name: "b"}, &recordTask{Name: "c"})
+	if n := countSegments(t, dir); n != 4 {
+		t.Errorf("Expected 4 segments, got %d", n)
+	}
+
+	// Segments are removed once acknowledged.
+	runUntil(t, q, "a", "b", "c")
+	if n := countSegments(t, dir); n != 1 {
+		t.Errorf("Expected 1 segment, got %d", n)
+	}
+}
+
+func TestQueueCompact(t *testing.T) {
+	dir := tempDir(t)
+	q := openQueue(t, dir, 1)
+	enqueue(t, q, &recordTask{Name: "a", Fail: true}, &recordTask{Name: "b"}, &recordTask{Name: "c"})
+	runUntil(t, q, "a", "b", "c")
+
+	// The failed task keeps all the segments alive.
+	if n := countSegments(t, dir); n < 4 {
+		t.Errorf("Expected at least 4 segments, got %d", n)
+	}
+	if err := q.Compact(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := countSegments(t, dir); n != 2 {
+		t.Errorf("Expected 2 segments, got %d", n)
+	}
+	q.Close()
+
+	q = openQueue(t, dir, 1)
+	defer q.Close()
+	if q.Pending() != 1 {
+		t.Errorf("Expected 1 pending task, got %d", q.Pending())
+	}
+	if err := q.Compact(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	runUntil(t, q, "a")
+}
+
+func TestQueueCompactFailure(t *testing.T) {
+	dir := tempDir(t)
+	q := openQueue(t, dir, 0)
+	enqueue(t, q, &recordTask{Name: "a"}, &recordTask{Name: "b"})
+	if err := q.ack(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The new active segment cannot be created once the compacted segment is in place.
+	blocker := segmentPath(dir, 3)
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := q.Compact(); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := q.Enqueue(&recordTask{Name: "c"}); !errors.Is(err, safeconcurrencyerrors.ErrQueueBroken) {
+		t.Errorf("Expected ErrQueueBroken, got %v", err)
+	}
+	if err := q.Compact(); !errors.Is(err, safeconcurrencyerrors.ErrQueueBroken) {
+		t.Errorf("Expected ErrQueueBroken, got %v", err)
+	}
+	if err := q.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// The acknowledged task is not resurrected once the queue is re-opened.
+	if err := os.Remove(blocker); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q = openQueue(t, dir, 0)
+	defer q.Close()
+	if q.Pending() != 1 {
+		t.Errorf("Expected 1 pending task, got %d", q.Pending())
+	}
+	if err := q.Compact(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	runUntil(t, q, "b")
+}
+
+func TestQueueSyncInterval(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	q, err := Open(Config[*recorder, struct{}]{
+		Dir:      tempDir(t),
+		Registry: newTestRegistry(),
+		Sync:     SyncInterval,
+		Clock:    fake,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	enqueue(t, q, &recordTask{Name: "a"})
+	fake.WaitForTimers(1)
+	fake.Advance(DefaultSyncInterval)
+	fake.WaitForTimers(1)
+
+	if err := q.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := q.Enqueue(&recordTask{Name: "b"}); !errors.Is(err, safeconcurrencyerrors.ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+}
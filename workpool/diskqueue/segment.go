@@ -0,0 +1,164 @@
+package diskqueue
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Izzette/go-safeconcurrency/workpool/registry"
+)
+
+const (
+	// segmentSuffix is the file name suffix of segments.
+	segmentSuffix = ".seg"
+	// tempSuffix is the file name suffix of segments being written by compaction.
+	tempSuffix = ".tmp"
+	// headerSize is the size of the header of each record: the length of the payload and its CRC-32 checksum.
+	headerSize = 8
+	// maxRecordSize bounds the size of a payload, larger lengths are treated as corruption.
+	maxRecordSize = 1 << 30
+)
+
+// operation is the kind of a record.
+type operation byte
+
+const (
+	// opEnqueue records a task added to the queue.
+	opEnqueue operation = iota + 1
+	// opAck records that a task was executed successfully.
+	opAck
+)
+
+// record is an entry of the append-only log.
+type record struct {
+	Op   operation          `json:"op"`
+	ID   uint64             `json:"id"`
+	Task *registry.Envelope `json:"task,omitempty"`
+}
+
+// segment is a file of the append-only log.
+type segment struct {
+	index uint64
+	// live is the number of enqueued tasks in the segment which are not yet acknowledged.
+	live int
+}
+
+// segmentPath returns the path of the segment with the index in the directory.
+func segmentPath(dir string, index uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", index, segmentSuffix))
+}
+
+// listSegments returns the indexes of the segments in the directory in ascending order, removing the leftovers of
+// interrupted compactions.
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing queue segments: %w", err)
+	}
+
+	var indexes []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, tempSuffix) {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return nil, fmt.Errorf("removing interrupted compaction: %w", err)
+			}
+
+			continue
+		}
+		if !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		index, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	return indexes, nil
+}
+
+// encodeRecord encodes the record with its header.
+func encodeRecord(rec record) ([]byte, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("encoding queue record: %w", err)
+	}
+
+	buf := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	copy(buf[headerSize:], payload)
+
+	return buf, nil
+}
+
+// readSegment calls the function for each record of the segment at the path.
+// It returns the size of the valid records, and whether a partially written or corrupt record follows them.
+func readSegment(path string, fn func(record)) (int64, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false, fmt.Errorf("opening queue segment: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header := make([]byte, headerSize)
+	var valid int64
+	for {
+		if _, err := io.ReadFull(reader, header); errors.Is(err, io.EOF) {
+			return valid, false, nil
+		} else if errors.Is(err, io.ErrUnexpectedEOF) {
+			return valid, true, nil
+		} else if err != nil {
+			return valid, false, fmt.Errorf("reading queue segment: %w", err)
+		}
+
+		length := binary.BigEndian.Uint32(header[0:4])
+		if length > maxRecordSize {
+			return valid, true, nil
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(reader, payload); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return valid, true, nil
+		} else if err != nil {
+			return valid, false, fmt.Errorf("reading queue segment: %w", err)
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			return valid, true, nil
+		}
+
+		var rec record
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			return valid, true, nil
+		}
+		fn(rec)
+		valid += int64(headerSize + length)
+	}
+}
+
+// syncDir flushes the directory entries to disk, so that created, renamed, and removed segments are durable.
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("opening queue directory: %w", err)
+	}
+	defer file.Close()
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("syncing queue directory: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package workpool
+
+import (
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// FairScheduling selects the policy used to choose between tenants in a pool created with [WithFairScheduling].
+type FairScheduling int
+
+const (
+	// RoundRobin serves one task from each tenant with queued tasks in turn.
+	RoundRobin FairScheduling = iota
+
+	// WeightedFair serves tenants in proportion to their [TenantConfig.Weight].
+	// A tenant with a weight of 2 will be served twice as often as a tenant with a weight of 1 while both have tasks
+	// queued.
+	WeightedFair
+)
+
+// TenantConfig configures the limits applied to a single tenant of a pool created with [WithFairScheduling].
+type TenantConfig struct {
+	// Weight is the relative share of the pool given to the tenant under the [WeightedFair] policy.
+	// A weight of 0 is treated as 1.
+	Weight uint
+
+	// MaxQueued limits the number of tasks of the tenant which may be waiting for a worker.
+	// Tasks implementing [types.RejectableTask] (which includes all tasks submitted with the helpers of this package)
+	// submitted while the queue of the tenant is full are rejected with
+	// [safeconcurrencyerrors.ErrTenantQueueFull].
+	// Other tasks are held back until the queue of the tenant has room, blocking the requests channel in the meantime.
+	// A limit of 0 means the queue is unbounded.
+	MaxQueued uint
+
+	// MaxConcurrency limits the number of tasks of the tenant which may be executed concurrently.
+	// A limit of 0 means the tenant may use all the workers of the pool.
+	MaxConcurrency uint
+}
+
+// FairConfig configures the fair scheduling of tasks between tenants, see [WithFairScheduling].
+type FairConfig struct {
+	// Scheduling is the policy used to choose between tenants, [RoundRobin] by default.
+	Scheduling FairScheduling
+
+	// DefaultTenant is the configuration applied to tenants without an entry in Tenants.
+	DefaultTenant TenantConfig
+
+	// Tenants is the per-tenant configuration, keyed by tenant identifier.
+	Tenants map[string]TenantConfig
+}
+
+// WithFairScheduling is an [Option] placing each task submitted to the pool in a per-tenant queue, which are served
+// according to the [FairConfig].
+// This prevents a single noisy tenant filling the requests channel and starving the other tenants.
+//
+// The tenant of a task is obtained from [types.TenantTask], which is implemented by all the wrappers of
+// [github.com/Izzette/go-safeconcurrency/workpool/task].
+// These wrappers report the tenant of the wrapped task if it implements [types.TenantTask], or otherwise the tenant
+// attached to the task [context.Context] with [github.com/Izzette/go-safeconcurrency/workpool/task.WithTenant].
+// Tasks without a tenant belong to the tenant with the empty identifier.
+//
+// The requests channel is consumed continuously by a dispatcher goroutine, so that the [Submit] helpers keep working
+// and only block while the tenant queue is full (for tasks not implementing [types.RejectableTask]).
+// The requests buffer of [NewBuffered] is still used in front of the dispatcher.
+func WithFairScheduling[ResourceT any](config FairConfig) Option[ResourceT] {
+	return func(p *workerPool[ResourceT]) {
+		p.fair = &fairScheduler[ResourceT]{
+			config:   config,
+			tenants:  make(map[string]*tenantQueue[ResourceT]),
+			work:     make(chan fairItem[ResourceT]),
+			finished: make(chan string),
+		}
+	}
+}
+
+// fairItem is a task dispatched to a worker, along with the tenant which owns it.
+type fairItem[ResourceT any] struct {
+	task   types.ValuelessTask[ResourceT]
+	tenant string
+}
+
+// tenantQueue holds the queued tasks and scheduling state of a single tenant.
+type tenantQueue[ResourceT any] struct {
+	name    string
+	config  TenantConfig
+	queue   []types.ValuelessTask[ResourceT]
+	running uint
+	// pass is the virtual time of the tenant used by the WeightedFair policy.
+	pass float64
+}
+
+// eligible returns true if the tenant has queued tasks and is below its concurrency quota.
+func (q *tenantQueue[ResourceT]) eligible() bool {
+	return len(q.queue) > 0 && (q.config.MaxConcurrency == 0 || q.running < q.config.MaxConcurrency)
+}
+
+// stride returns the virtual time consumed by the tenant for each task dispatched.
+func (q *tenantQueue[ResourceT]) stride() float64 {
+	if q.config.Weight == 0 {
+		return 1
+	}
+
+	return 1 / float64(q.config.Weight)
+}
+
+// fairScheduler implements the per-tenant queues of [WithFairScheduling].
+// All of its state, except the channels, is owned by the dispatcher goroutine.
+type fairScheduler[ResourceT any] struct {
+	config FairConfig
+
+	// tenants contains the tenants with queued or running tasks.
+	tenants map[string]*tenantQueue[ResourceT]
+	// active contains the tenants with queued tasks in the order they should be considered.
+	active []*tenantQueue[ResourceT]
+	// virtualTime is the pass of the last tenant served by the WeightedFair policy.
+	virtualTime float64
+	// running is the total number of tasks dispatched to the workers which have not yet finished.
+	running uint
+
+	// work is consumed by the workers.
+	work chan fairItem[ResourceT]
+	// finished receives the tenant of each task when the worker finishes executing it.
+	finished chan string
+}
+
+// dispatch is the dispatcher goroutine, it moves tasks from the requests channel to the tenant queues, and from the
+// tenant queues to the workers.
+// It closes the work channel once the requests channel is closed and all tasks have finished.
+func (s *fairScheduler[ResourceT]) dispatch(requests <-chan types.ValuelessTask[ResourceT]) {
+	defer close(s.work)
+
+	// held is a task which could not be rejected while its tenant queue was full.
+	var held types.ValuelessTask[ResourceT]
+	for {
+		if held != nil && s.enqueue(held) {
+			held = nil
+		}
+
+		in := requests
+		if held != nil {
+			in = nil
+		}
+
+		var out chan fairItem[ResourceT]
+		next := s.peek()
+		if next != nil {
+			out = s.work
+		} else if requests == nil && held == nil && s.running == 0 {
+			// The pool is closed, and all the tasks have been executed.
+			return
+		}
+
+		var item fairItem[ResourceT]
+		if next != nil {
+			item = fairItem[ResourceT]{task: next.queue[0], tenant: next.name}
+		}
+
+		select {
+		case tsk, ok := <-in:
+			if !ok {
+				requests = nil
+
+				continue
+			}
+			if !s.enqueue(tsk) {
+				held = tsk
+			}
+		case out <- item:
+			s.pop(next)
+		case tenant := <-s.finished:
+			s.finish(tenant)
+		}
+	}
+}
+
+// enqueue places the task in the queue of its tenant.
+// It returns false if the queue is full and the task could not be rejected.
+func (s *fairScheduler[ResourceT]) enqueue(tsk types.ValuelessTask[ResourceT]) bool {
+	name := ""
+	if tenantTask, ok := tsk.(types.TenantTask); ok {
+		name = tenantTask.Tenant()
+	}
+
+	q := s.tenant(name)
+	if q.config.MaxQueued != 0 && uint(len(q.queue)) >= q.config.MaxQueued {
+		rejectable, ok := tsk.(types.RejectableTask)
+		if !ok {
+			return false
+		}
+		rejectable.Reject(safeconcurrencyerrors.ErrTenantQueueFull)
+		s.release(q)
+
+		return true
+	}
+
+	if len(q.queue) == 0 {
+		// The tenant becomes active, it must not be able to claim the virtual time it spent idle.
+		if q.pass < s.virtualTime {
+			q.pass = s.virtualTime
+		}
+		s.active = append(s.active, q)
+	}
+	q.queue = append(q.queue, tsk)
+
+	return true
+}
+
+// peek returns the tenant which should be served next, or nil if no tenant is eligible.
+func (s *fairScheduler[ResourceT]) peek() *tenantQueue[ResourceT] {
+	var next *tenantQueue[ResourceT]
+	for _, q := range s.active {
+		if !q.eligible() {
+			continue
+		}
+		if s.config.Scheduling != WeightedFair {
+			// Round-robin serves the first eligible tenant, tenants are moved to the back once served.
+			return q
+		}
+		if next == nil || q.pass < next.pass {
+			next = q
+		}
+	}
+
+	return next
+}
+
+// pop removes the head of the queue of the tenant once it has been dispatched to a worker.
+func (s *fairScheduler[ResourceT]) pop(q *tenantQueue[ResourceT]) {
+	q.queue[0] = nil
+	q.queue = q.queue[1:]
+	q.running++
+	s.running++
+
+	s.virtualTime = q.pass
+	q.pass += q.stride()
+
+	// Move the tenant to the back of the active tenants, or remove it if it has no more tasks queued.
+	for i, active := range s.active {
+		if active == q {
+			s.active = append(s.active[:i], s.active[i+1:]...)
+
+			break
+		}
+	}
+	if len(q.queue) > 0 {
+		s.active = append(s.active, q)
+	}
+}
+
+// finish records that a task of the tenant has finished executing.
+func (s *fairScheduler[ResourceT]) finish(name string) {
+	q := s.tenants[name]
+	q.running--
+	s.running--
+	s.release(q)
+}
+
+// tenant returns the state of the tenant, creating it if the tenant has no queued or running tasks.
+func (s *fairScheduler[ResourceT]) tenant(name string) *tenantQueue[ResourceT] {
+	if q, ok := s.tenants[name]; ok {
+		return q
+	}
+
+	config, ok := s.config.Tenants[name]
+	if !ok {
+		config = s.config.DefaultTenant
+	}
+	q := &tenantQueue[ResourceT]{name: name, config: config, pass: s.virtualTime}
+	s.tenants[name] = q
+
+	return q
+}
+
+// release forgets the tenant once it has no queued or running tasks, so that tenants do not accumulate.
+func (s *fairScheduler[ResourceT]) release(q *tenantQueue[ResourceT]) {
+	if len(q.queue) == 0 && q.running == 0 {
+		delete(s.tenants, q.name)
+	}
+}
+
+// fairWorker is a goroutine that executes tasks dispatched by the fair scheduler.
+func (p *workerPool[ResourceT]) fairWorker() {
+	defer p.wg.Done()
+
+	for {
+		// The worker only receives tasks while inside the gate, so that the tasks stay queued while the pool is paused.
+		pausing := p.gate.Enter()
+		select {
+		case item, ok := <-p.fair.work:
+			if !ok {
+				p.gate.Leave()
+
+				return
+			}
+			p.execute(item.task)
+			p.gate.Leave()
+			p.fair.finished <- item.tenant
+		case <-pausing:
+			p.gate.Leave()
+		}
+	}
+}
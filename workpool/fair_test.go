@@ -0,0 +1,224 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// recordingTask records its name in order of execution.
+type recordingTask struct {
+	name  string
+	mu    *sync.Mutex
+	order *[]string
+}
+
+func (t *recordingTask) Execute(ctx context.Context, _ any) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*t.order = append(*t.order, t.name)
+
+	return t.name, nil
+}
+
+// blockFunc returns a task function which signals when it has started and blocks until release is closed.
+func blockFunc(started chan<- struct{}, release <-chan struct{}) types.TaskFunc[any] {
+	return func(context.Context, any) error {
+		started <- struct{}{}
+		<-release
+
+		return nil
+	}
+}
+
+// submitOrdered occupies the single worker of the pool, submits the tasks in order, and then returns the order in
+// which they were executed.
+func submitOrdered(t *testing.T, p types.WorkerPool[any], tenants []string) []string {
+	t.Helper()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := SubmitFunc[any](context.Background(), p, blockFunc(started, release)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+	<-started
+
+	mu := &sync.Mutex{}
+	order := make([]string, 0, len(tenants))
+	counts := make(map[string]int)
+	for _, tenant := range tenants {
+		counts[tenant]++
+		ctx := task.WithTenant(context.Background(), tenant)
+		name := tenant + string(rune('0'+counts[tenant]))
+		tsk := &recordingTask{name: name, mu: mu, order: &order}
+		// The dispatcher always accepts requests, so the tasks are queued in the order they are sent.
+		valuelessTask, res := task.Wrap[any, string](ctx, tsk)
+		p.Requests() <- valuelessTask
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := res.Drain(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	close(release)
+	wg.Wait()
+
+	return order
+}
+
+func TestFairRoundRobin(t *testing.T) {
+	p := New[any](nil, 1, WithFairScheduling[any](FairConfig{}))
+	p.Start()
+	defer p.Close()
+
+	order := submitOrdered(t, p, []string{"a", "a", "a", "b", "b", "c"})
+	expected := []string{"a1", "b1", "c1", "a2", "b2", "a3"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, got %v", expected, order)
+	}
+}
+
+func TestFairWeighted(t *testing.T) {
+	p := New[any](nil, 1, WithFairScheduling[any](FairConfig{
+		Scheduling: WeightedFair,
+		Tenants:    map[string]TenantConfig{"a": {Weight: 2}},
+	}))
+	p.Start()
+	defer p.Close()
+
+	order := submitOrdered(t, p, []string{"a", "a", "a", "a", "a", "a", "b", "b", "b"})
+	expected := []string{"a1", "b1", "a2", "b2", "a3", "a4", "b3", "a5", "a6"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, got %v", expected, order)
+	}
+}
+
+func TestFairTenantQueueFull(t *testing.T) {
+	p := New[any](nil, 1, WithFairScheduling[any](FairConfig{
+		DefaultTenant: TenantConfig{MaxQueued: 1},
+	}))
+	p.Start()
+	defer p.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ctx := task.WithTenant(context.Background(), "noisy")
+	done := make(chan error, 2)
+	go func() { done <- SubmitFunc[any](ctx, p, blockFunc(started, release)) }()
+	<-started
+
+	// The first task is queued behind the running task.
+	queued, queuedResult := task.WrapFunc[any](ctx, func(context.Context, any) error { return nil })
+	p.Requests() <- queued
+
+	// The second task is rejected as the queue of the tenant is full.
+	err := SubmitFunc[any](ctx, p, func(context.Context, any) error { return nil })
+	if !errors.Is(err, safeconcurrencyerrors.ErrTenantQueueFull) {
+		t.Errorf("expected ErrTenantQueueFull, got %v", err)
+	}
+
+	// Other tenants are unaffected.
+	other, otherResult := task.WrapFunc[any](task.WithTenant(context.Background(), "quiet"),
+		func(context.Context, any) error { return nil })
+	p.Requests() <- other
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := queuedResult.Drain(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := otherResult.Drain(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFairTenantConcurrency(t *testing.T) {
+	p := New[any](nil, 2, WithFairScheduling[any](FairConfig{
+		Tenants: map[string]TenantConfig{"a": {MaxConcurrency: 1}},
+	}))
+	p.Start()
+	defer p.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ctxA := task.WithTenant(context.Background(), "a")
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { done <- SubmitFunc[any](ctxA, p, blockFunc(started, release)) }()
+	}
+	<-started
+
+	// Only one task of tenant "a" may run at once, so the second worker is free for tenant "b".
+	ctxB := task.WithTenant(context.Background(), "b")
+	if err := SubmitFunc[any](ctxB, p, func(context.Context, any) error { return nil }); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	close(release)
+	<-started
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+// tenantTask implements types.TenantTask directly.
+type tenantTask struct{ tenant string }
+
+func (t *tenantTask) Tenant() string { return t.tenant }
+
+func (t *tenantTask) Execute(ctx context.Context, _ any) (string, error) {
+	tenant, _ := task.TenantFromContext(ctx)
+
+	return t.tenant + tenant, nil
+}
+
+func TestFairTenantFromTask(t *testing.T) {
+	p := NewBuffered[any](nil, 1, 1, WithFairScheduling[any](FairConfig{}))
+	p.Start()
+	defer p.Close()
+
+	val, err := Submit[any, string](context.Background(), p, &tenantTask{tenant: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if val != "a" {
+		t.Errorf("expected %q, got %q", "a", val)
+	}
+}
+
+func TestFairCloseDrainsQueues(t *testing.T) {
+	p := New[any](nil, 1, WithFairScheduling[any](FairConfig{}))
+	p.Start()
+
+	results := make([]types.TaskResult[struct{}], 0, 5)
+	for i := 0; i < 5; i++ {
+		valuelessTask, res := task.WrapFunc[any](context.Background(), func(context.Context, any) error { return nil })
+		p.Requests() <- valuelessTask
+		results = append(results, res)
+	}
+	p.Close()
+
+	for _, res := range results {
+		if err := res.Drain(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package workpool
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// HedgeConfig configures [SubmitHedged].
+type HedgeConfig struct {
+	// Delay is the time to wait for an attempt to finish before sending a hedge, for example the p95 latency of the
+	// task.
+	Delay time.Duration
+
+	// MaxHedges is the maximum number of hedges sent in addition to the first attempt.
+	MaxHedges uint
+
+	// Clock is used to time the hedge delay, the [clock.Real] clock is used if nil.
+	Clock types.Clock
+
+	// Limiter bounds the number of hedges in flight, it may be nil in which case only [HedgeConfig.MaxHedges] bounds the
+	// hedges of each call.
+	// The same [HedgeLimiter] should be shared between the calls to [SubmitHedged] using the same pool.
+	Limiter *HedgeLimiter
+
+	// Stats records how often hedges are sent and win, it may be nil.
+	// The same [HedgeStats] may be shared between many calls to [SubmitHedged].
+	Stats *HedgeStats
+}
+
+// HedgeLimiter bounds the number of hedges in flight across the calls to [SubmitHedged] sharing it, see
+// [HedgeConfig.Limiter].
+// A hedge is in flight until it finishes executing, even if another attempt already won.
+// It is safe for concurrent use.
+type HedgeLimiter struct {
+	slots chan struct{}
+}
+
+// NewHedgeLimiter creates a [HedgeLimiter] allowing up to limit hedges in flight, for example a fraction of the
+// concurrency of the pool.
+func NewHedgeLimiter(limit uint) *HedgeLimiter {
+	return &HedgeLimiter{slots: make(chan struct{}, limit)}
+}
+
+// tryAcquire reserves a slot for a hedge without blocking, and returns true if it succeeded.
+// A nil limiter always succeeds.
+func (l *HedgeLimiter) tryAcquire() bool {
+	if l == nil {
+		return true
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release frees the slot of a hedge reserved with [HedgeLimiter.tryAcquire].
+func (l *HedgeLimiter) release() {
+	if l != nil {
+		<-l.slots
+	}
+}
+
+// HedgeStats records statistics about calls to [SubmitHedged].
+// It is safe for concurrent use, and the zero value is ready to use.
+type HedgeStats struct {
+	requests atomic.Uint64
+	hedges   atomic.Uint64
+	wins     atomic.Uint64
+	skipped  atomic.Uint64
+}
+
+// Requests returns the number of calls to [SubmitHedged].
+func (s *HedgeStats) Requests() uint64 {
+	return s.requests.Load()
+}
+
+// Hedges returns the number of hedges sent.
+func (s *HedgeStats) Hedges() uint64 {
+	return s.hedges.Load()
+}
+
+// Wins returns the number of calls won by a hedge rather than by the first attempt.
+func (s *HedgeStats) Wins() uint64 {
+	return s.wins.Load()
+}
+
+// Skipped returns the number of hedges which were not sent because the pool did not accept them immediately, or the
+// [HedgeLimiter] was full.
+func (s *HedgeStats) Skipped() uint64 {
+	return s.skipped.Load()
+}
+
+// SubmitHedged is a helper function to submit a [types.Task] to a [types.WorkerPool] and wait for the result, sending
+// duplicates of the task (hedges) if the first attempt has not finished within the [HedgeConfig.Delay].
+// The first successful attempt wins, and the [context.Context] of the other attempts is canceled with
+// [safeconcurrencyerrors.ErrHedgeLost].
+// If all the attempts sent fail, the error of the last attempt to fail is returned.
+// The same advisories as for [Submit] about context cancellation apply.
+//
+// # Concurrency
+//
+// Hedges are sent without blocking: a hedge is skipped if the [types.WorkerPool] does not accept it immediately, and
+// another attempt is made after the delay.
+// For an unbuffered pool, this means that no worker is idle, but buffered pools and pools with fair scheduling (see
+// [WithFairScheduling]) accept tasks while all their workers are busy.
+// Use a [HedgeLimiter] to bound the number of hedges in flight, so that hedges do not add unbounded load to a pool
+// which is already saturated.
+//
+// The task must be safe to execute concurrently with itself.
+func SubmitHedged[ResourceT any, ValueT any](
+	ctx context.Context,
+	pool types.WorkerPool[ResourceT],
+	tsk types.Task[ResourceT, ValueT],
+	config HedgeConfig,
+) (ValueT, error) {
+	var zero ValueT
+	stats := config.Stats
+	if stats == nil {
+		stats = &HedgeStats{}
+	}
+	stats.requests.Add(1)
+
+	// select is not deterministic, and may still send tasks even if the context has been canceled.
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return zero, err
+	}
+
+	// Cancel the attempts which did not win.
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(safeconcurrencyerrors.ErrHedgeLost)
+
+	attempts := make(chan hedgeAttempt[ValueT], config.MaxHedges+1)
+	valuelessTask, taskResult := task.Wrap[ResourceT, ValueT](ctx, tsk)
+	valuelessTask = task.Awaited(valuelessTask)
+
+	// The first attempt is submitted as with Submit.
+	select {
+	case <-ctx.Done():
+		//nolint:wrapcheck
+		return zero, context.Cause(ctx)
+	case pool.Requests() <- valuelessTask:
+	}
+	go awaitHedgeAttempt(0, taskResult, attempts, func() {})
+	inFlight := 1
+	sent := uint(0)
+
+	timer := clock.OrReal(config.Clock).NewTimer(config.Delay)
+	defer timer.Stop()
+	hedgeTimer := timer.C()
+	if config.MaxHedges == 0 {
+		hedgeTimer = nil
+	}
+
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			//nolint:wrapcheck
+			return zero, context.Cause(ctx)
+		case <-hedgeTimer:
+			if sendHedge(ctx, pool, tsk, config.Limiter, sent+1, attempts) {
+				sent++
+				inFlight++
+				stats.hedges.Add(1)
+			} else {
+				stats.skipped.Add(1)
+			}
+			if sent < config.MaxHedges {
+				timer.Reset(config.Delay)
+			} else {
+				hedgeTimer = nil
+			}
+		case attempt := <-attempts:
+			inFlight--
+			if attempt.err == nil {
+				if attempt.index > 0 {
+					stats.wins.Add(1)
+				}
+
+				return attempt.value, nil
+			}
+			lastErr = attempt.err
+			if inFlight == 0 {
+				return zero, lastErr
+			}
+		}
+	}
+}
+
+// sendHedge sends a hedge to the pool without blocking, if the [HedgeLimiter] has a slot available, and returns true if
+// it was sent.
+func sendHedge[ResourceT any, ValueT any](
+	ctx context.Context,
+	pool types.WorkerPool[ResourceT],
+	tsk types.Task[ResourceT, ValueT],
+	limiter *HedgeLimiter,
+	index uint,
+	attempts chan<- hedgeAttempt[ValueT],
+) bool {
+	if !limiter.tryAcquire() {
+		return false
+	}
+
+	valuelessTask, taskResult := task.Wrap[ResourceT, ValueT](ctx, tsk)
+	valuelessTask = task.Awaited(valuelessTask)
+	select {
+	case pool.Requests() <- valuelessTask:
+		go awaitHedgeAttempt(index, taskResult, attempts, limiter.release)
+
+		return true
+	default:
+		limiter.release()
+
+		return false
+	}
+}
+
+// hedgeAttempt is the outcome of a single attempt of [SubmitHedged].
+type hedgeAttempt[ValueT any] struct {
+	index uint
+	value ValueT
+	err   error
+}
+
+// awaitHedgeAttempt waits for the attempt to finish, calls done, and publishes its outcome to the buffered attempts
+// channel.
+func awaitHedgeAttempt[ValueT any](
+	index uint,
+	taskResult types.TaskResult[ValueT],
+	attempts chan<- hedgeAttempt[ValueT],
+	done func(),
+) {
+	value := <-taskResult.Results()
+	err := taskResult.Drain()
+	done()
+	attempts <- hedgeAttempt[ValueT]{index: index, value: value, err: err}
+}
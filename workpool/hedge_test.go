@@ -0,0 +1,201 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/clock"
+)
+
+// hedgedTask blocks on its first attempt until canceled or released, and returns its attempt number otherwise.
+type hedgedTask struct {
+	attempts atomic.Int32
+	started  chan struct{}
+	release  chan struct{}
+	canceled chan error
+	err      error
+}
+
+func newHedgedTask() *hedgedTask {
+	return &hedgedTask{started: make(chan struct{}, 4), release: make(chan struct{}), canceled: make(chan error, 4)}
+}
+
+func (t *hedgedTask) Execute(ctx context.Context, _ any) (int32, error) {
+	n := t.attempts.Add(1)
+	t.started <- struct{}{}
+	if n > 1 {
+		return n, t.err
+	}
+
+	select {
+	case <-ctx.Done():
+		t.canceled <- context.Cause(ctx)
+
+		return 0, context.Cause(ctx)
+	case <-t.release:
+		return n, t.err
+	}
+}
+
+func TestSubmitHedgedWins(t *testing.T) {
+	p := New[any](nil, 2)
+	p.Start()
+	defer p.Close()
+
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	stats := &HedgeStats{}
+	tsk := newHedgedTask()
+	go func() {
+		<-tsk.started
+		fake.WaitForTimers(1)
+		fake.Advance(time.Second)
+	}()
+
+	val, err := SubmitHedged[any, int32](context.Background(), p, tsk, HedgeConfig{
+		Delay:     time.Second,
+		MaxHedges: 1,
+		Clock:     fake,
+		Stats:     stats,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val != 2 {
+		t.Errorf("Expected the hedge to win, got attempt %d", val)
+	}
+	if err := <-tsk.canceled; !errors.Is(err, safeconcurrencyerrors.ErrHedgeLost) {
+		t.Errorf("Expected the first attempt to be canceled with ErrHedgeLost, got %v", err)
+	}
+	if stats.Requests() != 1 || stats.Hedges() != 1 || stats.Wins() != 1 || stats.Skipped() != 0 {
+		t.Errorf("Unexpected stats: requests=%d hedges=%d wins=%d skipped=%d",
+			stats.Requests(), stats.Hedges(), stats.Wins(), stats.Skipped())
+	}
+}
+
+func TestSubmitHedgedSkippedWhenSaturated(t *testing.T) {
+	p := New[any](nil, 1)
+	p.Start()
+	defer p.Close()
+
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	stats := &HedgeStats{}
+	tsk := newHedgedTask()
+	go func() {
+		<-tsk.started
+		fake.WaitForTimers(1)
+		fake.Advance(time.Second)
+		// The hedge is skipped as the only worker is busy, and the timer is re-armed.
+		fake.WaitForTimers(1)
+		close(tsk.release)
+	}()
+
+	val, err := SubmitHedged[any, int32](context.Background(), p, tsk, HedgeConfig{
+		Delay:     time.Second,
+		MaxHedges: 1,
+		Clock:     fake,
+		Stats:     stats,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val != 1 {
+		t.Errorf("Expected the first attempt to win, got attempt %d", val)
+	}
+	if stats.Hedges() != 0 || stats.Wins() != 0 || stats.Skipped() != 1 {
+		t.Errorf("Unexpected stats: hedges=%d wins=%d skipped=%d", stats.Hedges(), stats.Wins(), stats.Skipped())
+	}
+}
+
+func TestSubmitHedgedLimiter(t *testing.T) {
+	// The buffered pool with fair scheduling accepts the hedges while the only worker is busy, they must be bounded by
+	// the limiter.
+	p := NewBuffered[any](nil, 1, 2, WithFairScheduling[any](FairConfig{}))
+	p.Start()
+	defer p.Close()
+
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	stats := &HedgeStats{}
+	limiter := NewHedgeLimiter(1)
+	tsk := newHedgedTask()
+	go func() {
+		<-tsk.started
+		fake.WaitForTimers(1)
+		fake.Advance(time.Second)
+		// The second hedge is skipped as the first one is still in flight.
+		fake.WaitForTimers(1)
+		fake.Advance(time.Second)
+		fake.WaitForTimers(1)
+		close(tsk.release)
+	}()
+
+	_, err := SubmitHedged[any, int32](context.Background(), p, tsk, HedgeConfig{
+		Delay:     time.Second,
+		MaxHedges: 2,
+		Clock:     fake,
+		Stats:     stats,
+		Limiter:   limiter,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Hedges() != 1 || stats.Skipped() != 1 {
+		t.Errorf("Unexpected stats: hedges=%d skipped=%d", stats.Hedges(), stats.Skipped())
+	}
+
+	// The slot is released once the hedge finished, which it has once the pool is closed.
+	p.Close()
+	for deadline := time.Now().Add(time.Second); !limiter.tryAcquire(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the limiter slot to be released")
+		}
+	}
+}
+
+func TestSubmitHedgedAllFail(t *testing.T) {
+	p := New[any](nil, 2)
+	p.Start()
+	defer p.Close()
+
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	tsk := newHedgedTask()
+	tsk.err = errors.New("task error")
+	go func() {
+		<-tsk.started
+		fake.WaitForTimers(1)
+		fake.Advance(time.Second)
+		<-tsk.started
+		close(tsk.release)
+	}()
+
+	_, err := SubmitHedged[any, int32](context.Background(), p, tsk, HedgeConfig{
+		Delay:     time.Second,
+		MaxHedges: 1,
+		Clock:     fake,
+	})
+	if !errors.Is(err, tsk.err) {
+		t.Errorf("Expected %v, got %v", tsk.err, err)
+	}
+}
+
+func TestSubmitHedgedNoHedges(t *testing.T) {
+	p := New[any](nil, 1)
+	p.Start()
+	defer p.Close()
+
+	tsk := newHedgedTask()
+	close(tsk.release)
+	val, err := SubmitHedged[any, int32](context.Background(), p, tsk, HedgeConfig{Delay: time.Hour})
+	if err != nil || val != 1 {
+		t.Errorf("Expected attempt 1, got %d (%v)", val, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SubmitHedged[any, int32](ctx, p, tsk, HedgeConfig{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
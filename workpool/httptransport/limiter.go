@@ -0,0 +1,64 @@
+package httptransport
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// limiter is a rate limiter implementing the generic cell rate algorithm, which is equivalent to a token bucket.
+type limiter struct {
+	clock    types.Clock
+	interval time.Duration
+	// tolerance is the time a request may be sent ahead of its theoretical arrival time, allowing bursts.
+	tolerance time.Duration
+
+	mu *sync.Mutex
+	// tat is the theoretical arrival time of the next request.
+	tat time.Time
+}
+
+// newLimiter creates a limiter allowing one request per interval, with bursts of up to burst requests.
+func newLimiter(clk types.Clock, interval time.Duration, burst int) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &limiter{
+		clock:     clk,
+		interval:  interval,
+		tolerance: time.Duration(burst-1) * interval,
+		mu:        &sync.Mutex{},
+	}
+}
+
+// wait waits until a request may be sent, or the [context.Context] is canceled.
+// The request is accounted for even if the context is canceled while waiting.
+func (l *limiter) wait(ctx context.Context) error {
+	now := l.clock.Now()
+
+	l.mu.Lock()
+	tat := l.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	l.tat = tat.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := tat.Sub(now) - l.tolerance
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := l.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		//nolint:wrapcheck
+		return context.Cause(ctx)
+	case <-timer.C():
+		return nil
+	}
+}
@@ -0,0 +1,291 @@
+package httptransport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// DefaultConcurrency is the maximum number of concurrent requests to a host if [HostLimits.Concurrency] is 0.
+const DefaultConcurrency = 4
+
+// HostLimits limits the requests sent to a host.
+type HostLimits struct {
+	// Concurrency is the maximum number of concurrent requests to the host, [DefaultConcurrency] is used if 0.
+	// A request counts towards the limit until its response body is closed.
+	Concurrency int
+
+	// Interval is the average time between requests to the host, requests are not rate limited if 0.
+	Interval time.Duration
+
+	// Burst is the number of requests which may be sent at once despite the [HostLimits.Interval], 1 if 0.
+	Burst int
+}
+
+// Config configures a [Transport].
+type Config struct {
+	// Base is the [http.RoundTripper] sending the requests, [http.DefaultTransport] is used if nil.
+	Base http.RoundTripper
+
+	// Default are the limits of the hosts missing from [Config.Hosts].
+	Default HostLimits
+
+	// Hosts are the limits of specific hosts, keyed by the host of the request URL, see [url.URL.Host].
+	Hosts map[string]HostLimits
+
+	// Clock is used for rate limiting, the [clock.Real] clock is used if nil.
+	Clock types.Clock
+}
+
+// Transport is an [http.RoundTripper] sending the requests to each host through a dedicated [types.WorkerPool],
+// limiting their concurrency and optionally their rate.
+// Any [http.Client] can be limited by using a Transport as its [http.Client.Transport].
+//
+// The worker sending a request is released once its response body is closed, or the [context.Context] of the request
+// is canceled, so that response bodies are streamed without exceeding the concurrency limit.
+// As with any [http.RoundTripper], response bodies must be closed.
+type Transport struct {
+	base   http.RoundTripper
+	config Config
+	clock  types.Clock
+
+	// mu protects hosts and closed.
+	mu     *sync.Mutex
+	hosts  map[string]*host
+	closed bool
+	// pending are the requests being sent to the pools, which must not be closed until they are sent.
+	pending   *sync.WaitGroup
+	done      chan struct{}
+	closeOnce *sync.Once
+}
+
+// host holds the pool sending the requests to a host.
+type host struct {
+	pool types.WorkerPool[http.RoundTripper]
+	// limiter is nil if the host is not rate limited.
+	limiter *limiter
+}
+
+// New creates a [Transport].
+// The pools of the hosts are started as needed.
+func New(config Config) *Transport {
+	base := config.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{
+		base:      base,
+		config:    config,
+		clock:     clock.OrReal(config.Clock),
+		mu:        &sync.Mutex{},
+		hosts:     make(map[string]*host),
+		pending:   &sync.WaitGroup{},
+		done:      make(chan struct{}),
+		closeOnce: &sync.Once{},
+	}
+}
+
+// RoundTrip implements [http.RoundTripper.RoundTrip].
+// It returns [safeconcurrencyerrors.ErrPoolClosed] if the Transport is closed.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	h, err := t.host(req.URL.Host)
+	if err != nil {
+		closeRequestBody(req)
+
+		return nil, err
+	}
+
+	valuelessTask, taskResult := task.WrapStreaming[http.RoundTripper, *http.Response](
+		ctx,
+		&roundTripTask{req: req, limiter: h.limiter},
+		1,
+	)
+	valuelessTask = task.Awaited(valuelessTask)
+
+	// The pool may not be closed while the request is being sent.
+	select {
+	case <-ctx.Done():
+		t.pending.Done()
+		closeRequestBody(req)
+
+		//nolint:wrapcheck
+		return nil, context.Cause(ctx)
+	case <-t.done:
+		t.pending.Done()
+		closeRequestBody(req)
+
+		return nil, safeconcurrencyerrors.ErrPoolClosed
+	case h.pool.Requests() <- valuelessTask:
+		t.pending.Done()
+	}
+
+	// The task emits the response, or returns an error if the context is canceled.
+	if resp, ok := <-taskResult.Results(); ok {
+		return resp, nil
+	}
+
+	//nolint:wrapcheck
+	return nil, taskResult.Drain()
+}
+
+// CloseIdleConnections closes the idle connections of the base [http.RoundTripper], if it supports it.
+func (t *Transport) CloseIdleConnections() {
+	type closeIdler interface {
+		CloseIdleConnections()
+	}
+	if base, ok := t.base.(closeIdler); ok {
+		base.CloseIdleConnections()
+	}
+}
+
+// Close closes the pools of the hosts, waiting for the bodies of the responses to be closed.
+// Requests sent after Close fail with [safeconcurrencyerrors.ErrPoolClosed].
+// It is safe to call Close multiple times.
+func (t *Transport) Close() {
+	t.closeOnce.Do(t.close)
+}
+
+// close does not synchronize with [Transport.closeOnce].
+func (t *Transport) close() {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+
+	close(t.done)
+	t.pending.Wait()
+	for _, h := range t.hosts {
+		h.pool.Close()
+	}
+}
+
+// host returns the host for the request, creating it if needed, and registers a pending request.
+func (t *Transport) host(name string) (*host, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, safeconcurrencyerrors.ErrPoolClosed
+	}
+	t.pending.Add(1)
+
+	if h, ok := t.hosts[name]; ok {
+		return h, nil
+	}
+
+	limits, ok := t.config.Hosts[name]
+	if !ok {
+		limits = t.config.Default
+	}
+	concurrency := limits.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	h := &host{pool: workpool.New[http.RoundTripper](t.base, concurrency)}
+	if limits.Interval > 0 {
+		h.limiter = newLimiter(t.clock, limits.Interval, limits.Burst)
+	}
+	h.pool.Start()
+	t.hosts[name] = h
+
+	return h, nil
+}
+
+// closeRequestBody closes the body of a request which will not be sent, as required of an [http.RoundTripper].
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+// roundTripTask implements [types.StreamingTask].
+// It sends the request, emits the response, and waits for the response body to be closed.
+type roundTripTask struct {
+	req     *http.Request
+	limiter *limiter
+}
+
+// Execute implements [types.StreamingTask.Execute].
+func (t *roundTripTask) Execute(
+	ctx context.Context,
+	base http.RoundTripper,
+	emitter types.Emitter[*http.Response],
+) error {
+	if t.limiter != nil {
+		if err := t.limiter.wait(ctx); err != nil {
+			closeRequestBody(t.req)
+
+			return err
+		}
+	}
+
+	resp, err := base.RoundTrip(t.req)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+
+	b := newBody(resp.Body)
+	resp.Body = b.wrap()
+	if err := emitter.Emit(ctx, resp); err != nil {
+		_ = b.Close()
+
+		//nolint:wrapcheck
+		return err
+	}
+
+	select {
+	case <-b.closed:
+	case <-ctx.Done():
+		// The response is abandoned, or its body can no longer be read.
+		_ = b.Close()
+	}
+
+	return nil
+}
+
+// body is a response body signaling when it is closed.
+type body struct {
+	io.ReadCloser
+	closed    chan struct{}
+	closeOnce *sync.Once
+}
+
+// newBody creates a body wrapping the response body.
+func newBody(rc io.ReadCloser) *body {
+	return &body{ReadCloser: rc, closed: make(chan struct{}), closeOnce: &sync.Once{}}
+}
+
+// Close closes the response body, and signals it is closed.
+func (b *body) Close() error {
+	err := b.ReadCloser.Close()
+	b.closeOnce.Do(func() { close(b.closed) })
+
+	//nolint:wrapcheck
+	return err
+}
+
+// wrap returns the body, preserving the [io.Writer] of the bodies of "101 Switching Protocols" responses.
+func (b *body) wrap() io.ReadCloser {
+	if w, ok := b.ReadCloser.(io.Writer); ok {
+		return &writableBody{body: b, Writer: w}
+	}
+
+	return b
+}
+
+// writableBody is a [body] which may be written to.
+type writableBody struct {
+	*body
+	io.Writer
+}
@@ -0,0 +1,151 @@
+package httptransport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// newServer starts an HTTP server, skipping the test in short mode as the loopback network is slow.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping HTTP server test in short mode")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello "+r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func get(t *testing.T, ctx context.Context, client *http.Client, url string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return resp
+}
+
+// busy returns true if all the workers of the pool of the host are busy.
+func busy(transport *Transport, server *httptest.Server) bool {
+	serverURL, _ := url.Parse(server.URL)
+	transport.mu.Lock()
+	h := transport.hosts[serverURL.Host]
+	transport.mu.Unlock()
+
+	noop, _ := task.WrapFunc[http.RoundTripper](context.Background(), func(context.Context, http.RoundTripper) error {
+		return nil
+	})
+	select {
+	case h.pool.Requests() <- noop:
+		return false
+	default:
+		return true
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := newServer(t)
+	transport := New(Config{Default: HostLimits{Concurrency: 1}})
+	defer transport.Close()
+	client := &http.Client{Transport: transport}
+
+	resp := get(t, context.Background(), client, server.URL+"/world")
+	// The worker is held until the body is closed.
+	if !busy(transport, server) {
+		t.Error("Expected the worker to be busy while the body is open")
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(body) != "hello /world" {
+		t.Errorf("Expected %q, got %q", "hello /world", body)
+	}
+	resp.Body.Close()
+
+	// The next request may only be sent once the worker is released.
+	resp = get(t, context.Background(), client, server.URL+"/again")
+	resp.Body.Close()
+}
+
+func TestTransportCancelReleasesWorker(t *testing.T) {
+	server := newServer(t)
+	transport := New(Config{Default: HostLimits{Concurrency: 1}})
+	defer transport.Close()
+	client := &http.Client{Transport: transport}
+
+	// The body of the canceled request is never closed by the caller.
+	ctx, cancel := context.WithCancel(context.Background())
+	get(t, ctx, client, server.URL)
+	cancel()
+
+	resp := get(t, context.Background(), client, server.URL)
+	resp.Body.Close()
+}
+
+func TestTransportRateLimit(t *testing.T) {
+	server := newServer(t)
+	fake := clock.NewFake(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	serverURL, _ := url.Parse(server.URL)
+	transport := New(Config{
+		Hosts: map[string]HostLimits{serverURL.Host: {Interval: time.Second, Burst: 2}},
+		Clock: fake,
+	})
+	defer transport.Close()
+	client := &http.Client{Transport: transport}
+
+	// The burst is sent immediately.
+	get(t, context.Background(), client, server.URL).Body.Close()
+	get(t, context.Background(), client, server.URL).Body.Close()
+
+	done := make(chan *http.Response, 1)
+	go func() { done <- get(t, context.Background(), client, server.URL) }()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	(<-done).Body.Close()
+}
+
+func TestTransportErrors(t *testing.T) {
+	baseErr := errors.New("base error")
+	transport := New(Config{Base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, baseErr
+	})})
+	client := &http.Client{Transport: transport}
+
+	if _, err := client.Get("http://example.invalid"); !errors.Is(err, baseErr) {
+		t.Errorf("Expected the base error, got %v", err)
+	}
+
+	transport.Close()
+	if _, err := client.Get("http://example.invalid"); !errors.Is(err, safeconcurrencyerrors.ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
+
+// roundTripperFunc is an [http.RoundTripper] implemented by a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
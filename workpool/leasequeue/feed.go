@@ -0,0 +1,129 @@
+package leasequeue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// FeedConfig configures [Feed].
+type FeedConfig struct {
+	// NackDelay is the delay before the message of a failed task becomes visible again.
+	NackDelay time.Duration
+
+	// OnError is called with the errors of the tasks, and the errors encountered acknowledging them.
+	OnError func(error)
+}
+
+// leaseKey is the [context.Context] key used to store the [Lease] of a task executed by [Feed].
+type leaseKey struct{}
+
+// LeaseFromContext returns the [Lease] of the message being processed by a task executed by [Feed].
+// Long running tasks may use it to call [Lease.ExtendLease] periodically, as a heartbeat.
+// The boolean is false if the context does not carry a lease of this type.
+func LeaseFromContext[ResourceT any, ValueT any](ctx context.Context) (*Lease[ResourceT, ValueT], bool) {
+	lease, ok := ctx.Value(leaseKey{}).(*Lease[ResourceT, ValueT])
+
+	return lease, ok
+}
+
+// Feed processes the messages of the [Queue] in the [types.WorkerPool], until the [context.Context] is canceled or the
+// queue is closed.
+// It returns the [context.Cause] if the context is canceled, or nil if the queue is closed.
+//
+// Messages are received by Feed itself, and their tasks are sent to the pool one at a time: the next message is only
+// received once the pool accepted the previous task, so that no worker is held waiting for messages and the pool may be
+// shared with other work.
+// The lease of a message is renewed for [Config.VisibilityTimeout] when its task starts executing, so that the time
+// spent waiting for a worker does not shorten it, and the task is skipped if the lease expired in the meantime.
+// A message waiting for a worker when the context is canceled is released immediately, see [Lease.Nack].
+//
+// The tasks are executed with the context, which also carries the [Lease] (see [LeaseFromContext]).
+// A message is acknowledged once its task succeeds, and negatively acknowledged with [FeedConfig.NackDelay] if it
+// fails.
+func Feed[ResourceT any, ValueT any](
+	ctx context.Context,
+	q *Queue[ResourceT, ValueT],
+	pool types.WorkerPool[ResourceT],
+	config FeedConfig,
+) error {
+	for {
+		lease, err := q.Receive(ctx)
+		if errors.Is(err, safeconcurrencyerrors.ErrQueueClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		leased := &leasedTask[ResourceT, ValueT]{ctx: ctx, lease: lease, config: config}
+		select {
+		case <-ctx.Done():
+			leased.release()
+
+			//nolint:wrapcheck
+			return context.Cause(ctx)
+		case <-q.done:
+			return nil
+		case pool.Requests() <- leased:
+		}
+	}
+}
+
+// leasedTask is a [types.ValuelessTask] executing the task of a message received by [Feed].
+type leasedTask[ResourceT any, ValueT any] struct {
+	//nolint:containedctx
+	ctx    context.Context
+	lease  *Lease[ResourceT, ValueT]
+	config FeedConfig
+}
+
+// Execute implements [types.ValuelessTask.Execute].
+func (t *leasedTask[ResourceT, ValueT]) Execute(resource ResourceT) {
+	lease := t.lease
+	if context.Cause(t.ctx) != nil {
+		t.release()
+
+		return
+	}
+	// The message may have been received again if its lease expired while waiting for a worker.
+	if err := lease.ExtendLease(lease.queue.visibilityTimeout); err != nil {
+		if !errors.Is(err, safeconcurrencyerrors.ErrQueueClosed) {
+			t.reportError(fmt.Errorf("renewing lease of message %d: %w", lease.ID(), err))
+		}
+
+		return
+	}
+
+	if _, err := lease.Task().Execute(context.WithValue(t.ctx, leaseKey{}, lease), resource); err != nil {
+		t.reportError(fmt.Errorf("message %d failed: %w", lease.ID(), err))
+		if err := lease.Nack(err, t.config.NackDelay); err != nil {
+			t.reportError(fmt.Errorf("releasing message %d: %w", lease.ID(), err))
+		}
+
+		return
+	}
+
+	if err := lease.Ack(); err != nil {
+		t.reportError(fmt.Errorf("acknowledging message %d: %w", lease.ID(), err))
+	}
+}
+
+// release makes the message visible again without executing its task, as the context was canceled.
+func (t *leasedTask[ResourceT, ValueT]) release() {
+	err := t.lease.Nack(nil, 0)
+	if err != nil && !errors.Is(err, safeconcurrencyerrors.ErrQueueClosed) {
+		t.reportError(fmt.Errorf("releasing message %d: %w", t.lease.ID(), err))
+	}
+}
+
+// reportError calls [FeedConfig.OnError] if set.
+func (t *leasedTask[ResourceT, ValueT]) reportError(err error) {
+	if t.config.OnError != nil {
+		t.config.OnError(err)
+	}
+}
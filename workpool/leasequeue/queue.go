@@ -0,0 +1,475 @@
+package leasequeue
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// DefaultVisibilityTimeout is the default [Config.VisibilityTimeout].
+const DefaultVisibilityTimeout = 30 * time.Second
+
+// Config configures a [Queue].
+type Config[ResourceT any] struct {
+	// VisibilityTimeout is the duration of a lease, [DefaultVisibilityTimeout] if 0.
+	VisibilityTimeout time.Duration
+
+	// MaxReceives is the number of times a message may be received, after which it is sent to the [DeadLetters]
+	// instead of becoming visible again.
+	// Messages may be received any number of times if 0.
+	MaxReceives uint
+
+	// DeadLetters receives the messages which exceeded [Config.MaxReceives], with an error wrapping
+	// [safeconcurrencyerrors.ErrMaxReceivesExceeded].
+	// The messages are discarded if nil.
+	DeadLetters types.DeadLetterSink[ResourceT]
+
+	// Clock is used to expire the leases, the [clock.Real] clock is used if nil.
+	Clock types.Clock
+}
+
+// Queue is an in-memory work queue with visibility timeouts.
+// A message received with [Queue.Receive] is leased: it is invisible to the other receivers until the [Lease] is
+// acknowledged, negatively acknowledged, or expires, in which case it becomes visible again.
+// This allows a stalled consumer to be detected without it crashing, its message being received by another consumer
+// once the [Config.VisibilityTimeout] elapses.
+// Expired leases are detected as soon as they expire, even when no consumer is receiving.
+// Use [Feed] to process the messages in a [types.WorkerPool].
+//
+// Messages are received in the order they became visible.
+// It is safe for concurrent use.
+type Queue[ResourceT any, ValueT any] struct {
+	visibilityTimeout time.Duration
+	maxReceives       uint
+	deadLetters       types.DeadLetterSink[ResourceT]
+	clock             types.Clock
+
+	// mu protects all the fields below.
+	mu      sync.Mutex
+	closed  bool
+	nextID  uint64
+	visible []*message[ResourceT, ValueT]
+	// invisible holds the leased and delayed messages, ordered by the time they become visible again.
+	invisible messageHeap[ResourceT, ValueT]
+	// sweeping is true while [Queue.sweep] is running.
+	sweeping bool
+	// changed is closed and replaced when a message may have become visible, or the queue is closed.
+	changed chan struct{}
+	// done is closed when the queue is closed.
+	done chan struct{}
+}
+
+// New creates a new empty [Queue].
+func New[ResourceT any, ValueT any](config Config[ResourceT]) *Queue[ResourceT, ValueT] {
+	visibilityTimeout := config.VisibilityTimeout
+	if visibilityTimeout <= 0 {
+		visibilityTimeout = DefaultVisibilityTimeout
+	}
+
+	return &Queue[ResourceT, ValueT]{
+		visibilityTimeout: visibilityTimeout,
+		maxReceives:       config.MaxReceives,
+		deadLetters:       config.DeadLetters,
+		clock:             clock.OrReal(config.Clock),
+		changed:           make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+}
+
+// Send adds the [types.Task] to the queue, and returns the identifier of its message.
+func (q *Queue[ResourceT, ValueT]) Send(tsk types.Task[ResourceT, ValueT]) (uint64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return 0, safeconcurrencyerrors.ErrQueueClosed
+	}
+
+	q.nextID++
+	q.visible = append(q.visible, &message[ResourceT, ValueT]{id: q.nextID, task: tsk, index: -1})
+	q.broadcast()
+
+	return q.nextID, nil
+}
+
+// Receive leases the next visible message, waiting for one to become visible if needed.
+// It returns the [context.Cause] if the [context.Context] is canceled first, or
+// [safeconcurrencyerrors.ErrQueueClosed] if the queue is closed.
+func (q *Queue[ResourceT, ValueT]) Receive(ctx context.Context) (*Lease[ResourceT, ValueT], error) {
+	for {
+		if err := context.Cause(ctx); err != nil {
+			//nolint:wrapcheck
+			return nil, err
+		}
+
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+
+			return nil, safeconcurrencyerrors.ErrQueueClosed
+		}
+		now := q.clock.Now()
+		letters := q.expire(now)
+		lease := q.lease(now)
+		changed := q.changed
+		var timer types.Timer
+		var wait <-chan time.Time
+		if lease == nil && len(q.invisible) > 0 {
+			timer = q.clock.NewTimer(q.invisible[0].visibleAt.Sub(now))
+			wait = timer.C()
+		}
+		q.mu.Unlock()
+
+		q.sendDeadLetters(letters)
+		if lease != nil {
+			return lease, nil
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		case <-wait:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// Visible returns the number of messages which may be received immediately.
+func (q *Queue[ResourceT, ValueT]) Visible() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.visible)
+}
+
+// Invisible returns the number of messages which are leased, or delayed by [Lease.Nack].
+func (q *Queue[ResourceT, ValueT]) Invisible() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.invisible)
+}
+
+// Close discards the messages of the queue, and wakes the blocked calls to [Queue.Receive].
+// The leases can no longer be acknowledged or extended.
+// It is safe to call Close multiple times.
+func (q *Queue[ResourceT, ValueT]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.done)
+	q.visible = nil
+	q.invisible = nil
+	q.broadcast()
+}
+
+// lease leases the first visible message, if any.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) lease(now time.Time) *Lease[ResourceT, ValueT] {
+	if len(q.visible) == 0 {
+		return nil
+	}
+
+	msg := q.visible[0]
+	q.visible[0] = nil
+	q.visible = q.visible[1:]
+	msg.receives++
+	msg.token++
+	msg.visibleAt = now.Add(q.visibilityTimeout)
+	heap.Push(&q.invisible, msg)
+	if !q.sweeping {
+		q.sweeping = true
+		go q.sweep()
+	}
+
+	return &Lease[ResourceT, ValueT]{queue: q, msg: msg, token: msg.token}
+}
+
+// sweep expires the invisible messages once their time comes, so that the expired leases become visible again or are
+// dead-lettered even when no receiver is waiting.
+// It runs while there are invisible messages, see [Queue.lease].
+func (q *Queue[ResourceT, ValueT]) sweep() {
+	for {
+		q.mu.Lock()
+		if q.closed || len(q.invisible) == 0 {
+			q.sweeping = false
+			q.mu.Unlock()
+
+			return
+		}
+		now := q.clock.Now()
+		visible := len(q.visible)
+		letters := q.expire(now)
+		if len(q.visible) > visible {
+			q.broadcast()
+		}
+		changed := q.changed
+		var timer types.Timer
+		var wait <-chan time.Time
+		if len(q.invisible) > 0 {
+			timer = q.clock.NewTimer(q.invisible[0].visibleAt.Sub(now))
+			wait = timer.C()
+		}
+		q.mu.Unlock()
+
+		q.sendDeadLetters(letters)
+		if timer == nil {
+			continue
+		}
+
+		select {
+		case <-changed:
+		case <-wait:
+		}
+		timer.Stop()
+	}
+}
+
+// expire makes the invisible messages whose time has come visible again, and returns the messages which exceeded the
+// maximum receive count.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) expire(now time.Time) []types.DeadLetter[ResourceT] {
+	var letters []types.DeadLetter[ResourceT]
+	for len(q.invisible) > 0 && !q.invisible[0].visibleAt.After(now) {
+		//nolint:forcetypeassert
+		msg := heap.Pop(&q.invisible).(*message[ResourceT, ValueT])
+		if letter, dead := q.makeVisible(msg); dead {
+			letters = append(letters, letter)
+		}
+	}
+
+	return letters
+}
+
+// makeVisible releases the lease of the message, and makes it visible again unless it exceeded the maximum receive
+// count, in which case its dead letter is returned.
+// The message must not be in the heap of invisible messages.
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) makeVisible(msg *message[ResourceT, ValueT]) (types.DeadLetter[ResourceT], bool) {
+	// Invalidate the current lease.
+	msg.token++
+
+	if q.maxReceives == 0 || msg.receives < q.maxReceives {
+		q.visible = append(q.visible, msg)
+
+		return types.DeadLetter[ResourceT]{}, false
+	}
+
+	err := error(safeconcurrencyerrors.ErrMaxReceivesExceeded)
+	if msg.err != nil {
+		err = fmt.Errorf("%w: %w", safeconcurrencyerrors.ErrMaxReceivesExceeded, msg.err)
+	}
+	valuelessTask, _ := task.Wrap(context.Background(), msg.task)
+
+	return types.DeadLetter[ResourceT]{Task: valuelessTask, Err: err, Attempts: msg.receives}, true
+}
+
+// sendDeadLetters sends the dead letters to the sink, it must be called without holding the lock.
+func (q *Queue[ResourceT, ValueT]) sendDeadLetters(letters []types.DeadLetter[ResourceT]) {
+	if q.deadLetters == nil {
+		return
+	}
+	for _, letter := range letters {
+		q.deadLetters.Put(letter)
+	}
+}
+
+// broadcast wakes all the blocked calls to [Queue.Receive].
+// The caller must hold the lock.
+func (q *Queue[ResourceT, ValueT]) broadcast() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// Lease is a message received from a [Queue].
+// Exactly one of [Lease.Ack] or [Lease.Nack] should be called once the message is processed, and
+// [Lease.ExtendLease] may be called in the meantime to keep the message invisible for longer.
+// These methods return [safeconcurrencyerrors.ErrLeaseExpired] if the lease expired, in which case the message may
+// have been received by another consumer.
+type Lease[ResourceT any, ValueT any] struct {
+	queue *Queue[ResourceT, ValueT]
+	msg   *message[ResourceT, ValueT]
+	token uint64
+}
+
+// ID returns the identifier of the message.
+func (l *Lease[ResourceT, ValueT]) ID() uint64 {
+	return l.msg.id
+}
+
+// Task returns the [types.Task] of the message.
+func (l *Lease[ResourceT, ValueT]) Task() types.Task[ResourceT, ValueT] {
+	return l.msg.task
+}
+
+// Receives returns the number of times the message was received, including this lease.
+func (l *Lease[ResourceT, ValueT]) Receives() uint {
+	l.queue.mu.Lock()
+	defer l.queue.mu.Unlock()
+
+	return l.msg.receives
+}
+
+// Deadline returns the time at which the lease expires.
+func (l *Lease[ResourceT, ValueT]) Deadline() time.Time {
+	l.queue.mu.Lock()
+	defer l.queue.mu.Unlock()
+
+	return l.msg.visibleAt
+}
+
+// Ack acknowledges the message, removing it from the [Queue].
+func (l *Lease[ResourceT, ValueT]) Ack() error {
+	q := l.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := l.check(); err != nil {
+		return err
+	}
+	l.msg.token++
+	heap.Remove(&q.invisible, l.msg.index)
+
+	return nil
+}
+
+// Nack releases the message, which becomes visible again after the delay, or immediately if the delay is 0.
+// The cause is reported in the dead letter of the message if it exceeds [Config.MaxReceives], it may be nil.
+func (l *Lease[ResourceT, ValueT]) Nack(cause error, delay time.Duration) error {
+	q := l.queue
+	q.mu.Lock()
+
+	if err := l.check(); err != nil {
+		q.mu.Unlock()
+
+		return err
+	}
+	l.msg.err = cause
+	if delay > 0 {
+		// The lease is released, but the message stays invisible until the delay elapses.
+		l.msg.token++
+		l.msg.visibleAt = q.clock.Now().Add(delay)
+		heap.Fix(&q.invisible, l.msg.index)
+		q.broadcast()
+		q.mu.Unlock()
+
+		return nil
+	}
+
+	heap.Remove(&q.invisible, l.msg.index)
+	letter, dead := q.makeVisible(l.msg)
+	q.broadcast()
+	q.mu.Unlock()
+
+	if dead {
+		q.sendDeadLetters([]types.DeadLetter[ResourceT]{letter})
+	}
+
+	return nil
+}
+
+// ExtendLease extends the lease so that it expires after the duration from now.
+func (l *Lease[ResourceT, ValueT]) ExtendLease(d time.Duration) error {
+	q := l.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := l.check(); err != nil {
+		return err
+	}
+	l.msg.visibleAt = q.clock.Now().Add(d)
+	heap.Fix(&q.invisible, l.msg.index)
+	q.broadcast()
+
+	return nil
+}
+
+// check returns an error if the lease is no longer held, expiring the lease if its deadline has passed.
+// The caller must hold the lock.
+func (l *Lease[ResourceT, ValueT]) check() error {
+	q := l.queue
+	if q.closed {
+		return safeconcurrencyerrors.ErrQueueClosed
+	}
+	if l.msg.token != l.token {
+		return safeconcurrencyerrors.ErrLeaseExpired
+	}
+	if !l.msg.visibleAt.After(q.clock.Now()) {
+		// The lease expired, but no receiver noticed yet.
+		return safeconcurrencyerrors.ErrLeaseExpired
+	}
+
+	return nil
+}
+
+// message is a task in a [Queue].
+type message[ResourceT any, ValueT any] struct {
+	id   uint64
+	task types.Task[ResourceT, ValueT]
+	// receives is the number of times the message was leased.
+	receives uint
+	// token identifies the current lease, it is incremented when the lease is released.
+	token uint64
+	// visibleAt is the time at which the message becomes visible again while it is invisible.
+	visibleAt time.Time
+	// err is the cause of the last negative acknowledgement.
+	err error
+	// index is the position of the message in the heap of invisible messages, or -1 if it is not in the heap.
+	index int
+}
+
+// messageHeap implements [heap.Interface] ordering messages by the time they become visible, and identifier.
+type messageHeap[ResourceT any, ValueT any] []*message[ResourceT, ValueT]
+
+// Len implements [heap.Interface.Len].
+func (h messageHeap[ResourceT, ValueT]) Len() int {
+	return len(h)
+}
+
+// Less implements [heap.Interface.Less].
+func (h messageHeap[ResourceT, ValueT]) Less(i, j int) bool {
+	if h[i].visibleAt.Equal(h[j].visibleAt) {
+		return h[i].id < h[j].id
+	}
+
+	return h[i].visibleAt.Before(h[j].visibleAt)
+}
+
+// Swap implements [heap.Interface.Swap].
+func (h messageHeap[ResourceT, ValueT]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push implements [heap.Interface.Push].
+func (h *messageHeap[ResourceT, ValueT]) Push(x any) {
+	//nolint:forcetypeassert
+	msg := x.(*message[ResourceT, ValueT])
+	msg.index = len(*h)
+	*h = append(*h, msg)
+}
+
+// Pop implements [heap.Interface.Pop].
+func (h *messageHeap[ResourceT, ValueT]) Pop() any {
+	old := *h
+	msg := old[len(old)-1]
+	old[len(old)-1] = nil
+	msg.index = -1
+	*h = old[:len(old)-1]
+
+	return msg
+}
@@ -0,0 +1,306 @@
+package leasequeue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+)
+
+var epoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// namedTask returns its name, and fails if requested.
+type namedTask struct {
+	name string
+	err  error
+	ran  chan string
+}
+
+func (t *namedTask) Execute(ctx context.Context, _ any) (string, error) {
+	if t.ran != nil {
+		t.ran <- t.name
+	}
+
+	return t.name, t.err
+}
+
+// channelSink is a [types.DeadLetterSink] publishing the dead letters to a channel.
+type channelSink chan types.DeadLetter[any]
+
+func (s channelSink) Put(letter types.DeadLetter[any]) {
+	s <- letter
+}
+
+func receive(t *testing.T, q *Queue[any, string]) *Lease[any, string] {
+	t.Helper()
+
+	lease, err := q.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return lease
+}
+
+func TestQueueAck(t *testing.T) {
+	q := New[any, string](Config[any]{})
+	defer q.Close()
+
+	q.Send(&namedTask{name: "a"})
+	q.Send(&namedTask{name: "b"})
+	lease := receive(t, q)
+	if lease.ID() != 1 || lease.Receives() != 1 {
+		t.Errorf("Expected first message received once, got id %d received %d", lease.ID(), lease.Receives())
+	}
+	if q.Visible() != 1 || q.Invisible() != 1 {
+		t.Errorf("Expected 1 visible and 1 invisible message, got %d and %d", q.Visible(), q.Invisible())
+	}
+	if err := lease.Ack(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := lease.Ack(); !errors.Is(err, safeconcurrencyerrors.ErrLeaseExpired) {
+		t.Errorf("Expected ErrLeaseExpired on second ack, got %v", err)
+	}
+	if q.Invisible() != 0 {
+		t.Errorf("Expected no invisible message, got %d", q.Invisible())
+	}
+}
+
+func TestQueueVisibilityTimeout(t *testing.T) {
+	fake := clock.NewFake(epoch)
+	q := New[any, string](Config[any]{VisibilityTimeout: time.Minute, Clock: fake})
+	defer q.Close()
+
+	q.Send(&namedTask{name: "a"})
+	stalled := receive(t, q)
+
+	// The stalled lease is extended once, then expires.
+	fake.Advance(30 * time.Second)
+	if err := stalled.ExtendLease(time.Minute); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if expected := epoch.Add(90 * time.Second); !stalled.Deadline().Equal(expected) {
+		t.Errorf("Expected deadline %v, got %v", expected, stalled.Deadline())
+	}
+
+	received := make(chan *Lease[any, string], 1)
+	go func() { received <- receive(t, q) }()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Minute)
+
+	lease := <-received
+	if lease.ID() != stalled.ID() || lease.Receives() != 2 {
+		t.Errorf("Expected the message to be received again, got id %d received %d", lease.ID(), lease.Receives())
+	}
+	if err := stalled.Ack(); !errors.Is(err, safeconcurrencyerrors.ErrLeaseExpired) {
+		t.Errorf("Expected ErrLeaseExpired, got %v", err)
+	}
+	if err := stalled.ExtendLease(time.Minute); !errors.Is(err, safeconcurrencyerrors.ErrLeaseExpired) {
+		t.Errorf("Expected ErrLeaseExpired, got %v", err)
+	}
+	if err := lease.Ack(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestQueueNack(t *testing.T) {
+	fake := clock.NewFake(epoch)
+	q := New[any, string](Config[any]{Clock: fake})
+	defer q.Close()
+
+	q.Send(&namedTask{name: "a"})
+	q.Send(&namedTask{name: "b"})
+	if err := receive(t, q).Nack(nil, 0); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	// The negatively acknowledged message goes to the back of the queue.
+	if lease := receive(t, q); lease.ID() != 2 {
+		t.Errorf("Expected message 2, got %d", lease.ID())
+	}
+
+	lease := receive(t, q)
+	if lease.ID() != 1 {
+		t.Errorf("Expected message 1, got %d", lease.ID())
+	}
+	if err := lease.Nack(nil, time.Second); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if q.Visible() != 0 {
+		t.Errorf("Expected the delayed message to be invisible, got %d visible", q.Visible())
+	}
+	fake.Advance(time.Second)
+	if lease := receive(t, q); lease.ID() != 1 || lease.Receives() != 3 {
+		t.Errorf("Expected message 1 received 3 times, got %d received %d", lease.ID(), lease.Receives())
+	}
+}
+
+func TestQueueMaxReceives(t *testing.T) {
+	fake := clock.NewFake(epoch)
+	sink := make(channelSink, 1)
+	q := New[any, string](Config[any]{MaxReceives: 2, DeadLetters: sink, Clock: fake})
+	defer q.Close()
+
+	q.Send(&namedTask{name: "a"})
+	taskErr := errors.New("task error")
+	receive(t, q).Nack(taskErr, 0)
+	receive(t, q).Nack(taskErr, 0)
+
+	letter := <-sink
+	if letter.Attempts != 2 || !errors.Is(letter.Err, safeconcurrencyerrors.ErrMaxReceivesExceeded) ||
+		!errors.Is(letter.Err, taskErr) {
+		t.Errorf("Unexpected dead letter %+v", letter)
+	}
+	if q.Visible() != 0 || q.Invisible() != 0 {
+		t.Errorf("Expected empty queue, got %d visible and %d invisible", q.Visible(), q.Invisible())
+	}
+
+	// Expired leases count as receives too.
+	q.Send(&namedTask{name: "b"})
+	receive(t, q)
+	fake.Advance(DefaultVisibilityTimeout)
+	receive(t, q)
+	fake.Advance(DefaultVisibilityTimeout)
+	// The expired message is dead-lettered without waiting for a receiver.
+	if letter := <-sink; !errors.Is(letter.Err, safeconcurrencyerrors.ErrMaxReceivesExceeded) {
+		t.Errorf("Unexpected dead letter %+v", letter)
+	}
+}
+
+func TestQueueMaxReceivesWithoutReceiver(t *testing.T) {
+	fake := clock.NewFake(epoch)
+	sink := make(channelSink, 1)
+	q := New[any, string](Config[any]{MaxReceives: 1, DeadLetters: sink, Clock: fake})
+	defer q.Close()
+
+	q.Send(&namedTask{name: "a"})
+	receive(t, q)
+	fake.WaitForTimers(1)
+	fake.Advance(DefaultVisibilityTimeout)
+	if letter := <-sink; letter.Attempts != 1 || !errors.Is(letter.Err, safeconcurrencyerrors.ErrMaxReceivesExceeded) {
+		t.Errorf("Unexpected dead letter %+v", letter)
+	}
+}
+
+func TestQueueClose(t *testing.T) {
+	q := New[any, string](Config[any]{})
+	q.Send(&namedTask{name: "a"})
+	lease := receive(t, q)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := q.Receive(context.Background())
+		done <- err
+	}()
+	q.Close()
+	if err := <-done; !errors.Is(err, safeconcurrencyerrors.ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+	if err := lease.Ack(); !errors.Is(err, safeconcurrencyerrors.ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+	if _, err := q.Send(&namedTask{name: "b"}); !errors.Is(err, safeconcurrencyerrors.ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+}
+
+func TestFeed(t *testing.T) {
+	q := New[any, string](Config[any]{MaxReceives: 1})
+	p := workpool.New[any](nil, 2)
+	p.Start()
+
+	ran := make(chan string, 2)
+	errs := make(chan error, 2)
+	done := make(chan error, 1)
+	go func() {
+		done <- Feed(context.Background(), q, p, FeedConfig{OnError: func(err error) { errs <- err }})
+	}()
+
+	q.Send(&namedTask{name: "a", ran: ran})
+	q.Send(&namedTask{name: "b", ran: ran, err: errors.New("task error")})
+	<-ran
+	<-ran
+	if err := <-errs; err == nil {
+		t.Error("Expected the task error to be reported")
+	}
+
+	q.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+	p.Close()
+	if q.Visible() != 0 || q.Invisible() != 0 {
+		t.Errorf("Expected empty queue, got %d visible and %d invisible", q.Visible(), q.Invisible())
+	}
+}
+
+func TestFeedSharedPool(t *testing.T) {
+	q := New[any, string](Config[any]{})
+	defer q.Close()
+	p := workpool.New[any](nil, 1)
+	p.Start()
+	defer p.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Feed(ctx, q, p, FeedConfig{}) }()
+
+	// The feeder waiting for messages must not hold the only worker of the pool.
+	submitCtx, submitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer submitCancel()
+	if err := workpool.SubmitFunc[any](submitCtx, p, func(context.Context, any) error { return nil }); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+type heartbeatTask struct {
+	deadlines chan time.Time
+}
+
+func (t heartbeatTask) Execute(ctx context.Context, _ any) (string, error) {
+	lease, ok := LeaseFromContext[any, string](ctx)
+	if !ok {
+		return "", errors.New("no lease in context")
+	}
+	err := lease.ExtendLease(time.Hour)
+	t.deadlines <- lease.Deadline()
+
+	return "", err
+}
+
+func TestFeedLeaseFromContext(t *testing.T) {
+	fake := clock.NewFake(epoch)
+	q := New[any, string](Config[any]{Clock: fake})
+	p := workpool.New[any](nil, 1)
+	p.Start()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Feed(ctx, q, p, FeedConfig{}) }()
+
+	deadlines := make(chan time.Time, 1)
+	q.Send(heartbeatTask{deadlines: deadlines})
+	if deadline := <-deadlines; !deadline.Equal(epoch.Add(time.Hour)) {
+		t.Errorf("Expected the lease to be extended, got deadline %v", deadline)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	// The workers acknowledge the message before exiting.
+	p.Close()
+	if q.Visible() != 0 || q.Invisible() != 0 {
+		t.Errorf("Expected empty queue, got %d visible and %d invisible", q.Visible(), q.Invisible())
+	}
+	q.Close()
+}
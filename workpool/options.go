@@ -0,0 +1,4 @@
+package workpool
+
+// Option configures optional behaviour of a [types.WorkerPool] created by [New] or [NewBuffered].
+type Option[ResourceT any] func(*workerPool[ResourceT])
@@ -1,17 +1,19 @@
 package workpool
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 
 	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/internal/safeconcurrencysync"
 )
 
 // New creates (but does not start) a basic implementation of [types.WorkerPool] with no requests buffering.
 // If you would like requests buffering, use [NewBuffered] instead.
 // It is equivalent to calling [NewBuffered] with a buffer size of 0.
-func New[ResourceT any](resource ResourceT, concurrency int) types.WorkerPool[ResourceT] {
-	return NewBuffered(resource, concurrency, 0)
+func New[ResourceT any](resource ResourceT, concurrency int, opts ...Option[ResourceT]) types.WorkerPool[ResourceT] {
+	return NewBuffered(resource, concurrency, 0, opts...)
 }
 
 // NewBuffered creates (but does not start) a basic implementation of [types.WorkerPool].
@@ -22,7 +24,14 @@ func New[ResourceT any](resource ResourceT, concurrency int) types.WorkerPool[Re
 // [context.Context] for the task is cancelled.
 //
 // The resource argument may be set to nil and ResourceT set to type any if a shared pool resource is not required.
-func NewBuffered[ResourceT any](resource ResourceT, concurrency int, buffer uint) types.WorkerPool[ResourceT] {
+//
+// Optional behaviour, such as [WithFairScheduling], may be enabled by passing one or more [Option].
+func NewBuffered[ResourceT any](
+	resource ResourceT,
+	concurrency int,
+	buffer uint,
+	opts ...Option[ResourceT],
+) types.WorkerPool[ResourceT] {
 	if concurrency <= 0 {
 		panic("Worker pool must have at least one worker!")
 	}
@@ -34,6 +43,10 @@ func NewBuffered[ResourceT any](resource ResourceT, concurrency int, buffer uint
 		wg:          &sync.WaitGroup{},
 		started:     &atomic.Bool{},
 		closeOnce:   &sync.Once{},
+		gate:        &safeconcurrencysync.PauseGate{},
+	}
+	for _, opt := range opts {
+		opt(pool)
 	}
 	// We will run concurrency workers when Start() is called.
 	// This WaitGroup must be pre-populated in the case that Wait() is called in another goroutine before Start().
@@ -50,6 +63,14 @@ type workerPool[ResourceT any] struct {
 	wg          *sync.WaitGroup
 	started     *atomic.Bool
 	closeOnce   *sync.Once
+	gate        *safeconcurrencysync.PauseGate
+
+	// fair is set by [WithFairScheduling], in which case the workers consume from the fair scheduler instead of the
+	// requests channel.
+	fair *fairScheduler[ResourceT]
+
+	// deadLetters is set by [WithDeadLetters].
+	deadLetters types.DeadLetterSink[ResourceT]
 }
 
 // Start implements [types.WorkerPool.Start].
@@ -60,15 +81,25 @@ func (p *workerPool[ResourceT]) Start() {
 		panic("attempt to start previously started worker pool")
 	}
 
+	if p.fair != nil {
+		go p.fair.dispatch(p.requests)
+	}
+
 	// The WaitGroup is already populated for the number of workers.
 	for i := uint(0); i < p.concurrency; i++ {
-		go p.worker()
+		if p.fair != nil {
+			go p.fairWorker()
+		} else {
+			go p.worker()
+		}
 	}
 }
 
 // Close implements [types.WorkerPool.Close].
 func (p *workerPool[ResourceT]) Close() {
 	p.closeOnce.Do(p.closeRequests)
+	// The queued tasks must be executed for the workers to exit.
+	p.gate.Resume()
 	if !p.started.Load() {
 		return
 	}
@@ -81,12 +112,40 @@ func (p *workerPool[ResourceT]) Requests() chan<- types.ValuelessTask[ResourceT]
 	return p.requests
 }
 
+// Pause implements [types.PausableWorkerPool.Pause].
+func (p *workerPool[ResourceT]) Pause(ctx context.Context) error {
+	//nolint:wrapcheck
+	return p.gate.Pause(ctx)
+}
+
+// Resume implements [types.PausableWorkerPool.Resume].
+func (p *workerPool[ResourceT]) Resume() {
+	p.gate.Resume()
+}
+
+// Paused implements [types.PausableWorkerPool.Paused].
+func (p *workerPool[ResourceT]) Paused() bool {
+	return p.gate.Paused()
+}
+
 // worker is a goroutine that executes tasks from the requests channel.
 func (p *workerPool[ResourceT]) worker() {
 	defer p.wg.Done()
 
-	for task := range p.requests {
-		task.Execute(p.resource)
+	for {
+		// The worker only receives tasks while inside the gate, so that the tasks stay queued while the pool is paused.
+		pausing := p.gate.Enter()
+		select {
+		case task, ok := <-p.requests:
+			if !ok {
+				p.gate.Leave()
+
+				return
+			}
+			p.execute(task)
+		case <-pausing:
+		}
+		p.gate.Leave()
 	}
 }
 
@@ -94,3 +153,10 @@ func (p *workerPool[ResourceT]) worker() {
 func (p *workerPool[ResourceT]) closeRequests() {
 	close(p.requests)
 }
+
+// execute runs the task, and routes it to the dead letter sink if it failed.
+// It must be called inside the pause gate.
+func (p *workerPool[ResourceT]) execute(task types.ValuelessTask[ResourceT]) {
+	task.Execute(p.resource)
+	p.routeDeadLetter(task)
+}
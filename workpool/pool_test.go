@@ -1,9 +1,12 @@
 package workpool
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
 )
 
 type countingTask struct {
@@ -53,3 +56,85 @@ func TestPoolSubmitAfterClose(t *testing.T) {
 	}()
 	p.Requests() <- &countingTask{}
 }
+
+func TestPoolPauseResume(t *testing.T) {
+	p := NewBuffered[any](nil, 1, 1).(types.PausableWorkerPool[any])
+	defer p.Close()
+	p.Start()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	running := make(chan error)
+	go func() { running <- SubmitFunc[any](context.Background(), p, blockFunc(started, release)) }()
+	<-started
+
+	// Pause waits for the running task to finish.
+	paused := make(chan error)
+	go func() { paused <- p.Pause(context.Background()) }()
+	close(release)
+	if err := <-running; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := <-paused; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !p.Paused() {
+		t.Error("Expected pool to be paused")
+	}
+
+	// Queued tasks are not executed while paused.
+	var count atomic.Int32
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	p.Requests() <- &countingTask{count: &count, wg: wg}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Pause(ctx); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if count.Load() != 0 {
+		t.Error("Expected no task to be executed while paused")
+	}
+
+	p.Resume()
+	wg.Wait()
+	if p.Paused() {
+		t.Error("Expected pool to be resumed")
+	}
+}
+
+func TestPoolPauseKeepsTasksQueued(t *testing.T) {
+	p := New[any](nil, 2).(types.PausableWorkerPool[any])
+	defer p.Close()
+	p.Start()
+	if err := p.Pause(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// No worker receives from the unbuffered requests channel while paused, submitters keep blocking.
+	select {
+	case p.Requests() <- &countingTask{}:
+		t.Error("Expected the submission to block while paused")
+	default:
+	}
+	p.Resume()
+}
+
+func TestPoolCloseWhilePaused(t *testing.T) {
+	p := NewBuffered[any](nil, 1, 1, WithFairScheduling[any](FairConfig{})).(types.PausableWorkerPool[any])
+	if err := p.Pause(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	p.Start()
+
+	var count atomic.Int32
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	p.Requests() <- &countingTask{count: &count, wg: wg}
+
+	// Close resumes the pool so that the queued task completes.
+	p.Close()
+	if count.Load() != 1 {
+		t.Errorf("Expected queued task to be executed, got %d executions", count.Load())
+	}
+}
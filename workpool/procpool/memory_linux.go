@@ -0,0 +1,29 @@
+//go:build linux
+
+package procpool
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+)
+
+// residentMemory returns the resident memory of the current process in bytes, including memory allocated outside of
+// the Go runtime, for example by cgo code.
+func residentMemory() uint64 {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return runtimeMemory()
+	}
+
+	fields := bytes.Fields(statm)
+	if len(fields) < 2 {
+		return runtimeMemory()
+	}
+	pages, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil {
+		return runtimeMemory()
+	}
+
+	return pages * uint64(os.Getpagesize())
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package procpool
+
+// residentMemory returns the memory obtained from the operating system by the Go runtime, in bytes.
+// Memory allocated outside of the Go runtime is not accounted for on this platform.
+func residentMemory() uint64 {
+	return runtimeMemory()
+}
@@ -0,0 +1,410 @@
+package procpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool/registry"
+)
+
+// Config configures a [Pool].
+type Config struct {
+	// Concurrency is the number of worker processes, it must be greater than 0.
+	Concurrency int
+
+	// MaxTasks is the number of tasks after which a worker process is recycled, if greater than 0.
+	MaxTasks uint
+
+	// MaxMemory is the resident memory in bytes above which a worker process is recycled after its current task, if
+	// greater than 0.
+	// On Linux, the resident set size of the process is used, which includes memory allocated by cgo code.
+	// On other platforms, only the memory obtained by the Go runtime is accounted for.
+	MaxMemory uint64
+
+	// CancelGracePeriod is the time a task is given to return once its [context.Context] is canceled, after which its
+	// worker process is killed and restarted, failing the task with the [context.Cause].
+	// Worker processes are never killed if 0.
+	CancelGracePeriod time.Duration
+
+	// Args are the arguments of the worker processes, the arguments of the current process are used if nil.
+	Args []string
+
+	// Env is added to the environment of the current process for the worker processes.
+	Env []string
+
+	// Clock is used for the [Config.CancelGracePeriod], the [clock.Real] clock is used if nil.
+	Clock types.Clock
+}
+
+// TaskError is the error returned for a task which failed in a worker process.
+// Only the message of the original error is preserved.
+type TaskError struct {
+	Message string
+}
+
+// Error implements the error interface for [TaskError].
+func (e *TaskError) Error() string {
+	return e.Message
+}
+
+// Pool is a worker pool executing tasks in child processes, isolating the current process from crashes and memory
+// leaks of the tasks, for example in cgo code.
+// The worker processes are re-executed copies of the current binary, which must call [ServeIfWorker] early on.
+// The tasks are serialized with a [registry.Registry], and exchanged with the worker processes over their standard
+// input and output using a length-prefixed gob protocol.
+//
+// Each worker process executes one task at a time.
+// Worker processes which crash are restarted, failing their current task with
+// [safeconcurrencyerrors.ErrWorkerCrashed], and worker processes are recycled after [Config.MaxTasks] tasks or once
+// they exceed [Config.MaxMemory].
+//
+// Use [Submit] to execute tasks in the pool.
+type Pool[ResourceT any, ValueT any] struct {
+	registry *registry.Registry[ResourceT, ValueT]
+	config   Config
+	clock    types.Clock
+
+	requests  chan *job[ValueT]
+	done      chan struct{}
+	nextID    *atomic.Uint64
+	restarts  *atomic.Uint64
+	recycles  *atomic.Uint64
+	wg        *sync.WaitGroup
+	started   *atomic.Bool
+	closeOnce *sync.Once
+}
+
+// New creates (but does not start) a [Pool] executing the tasks registered in the [registry.Registry].
+func New[ResourceT any, ValueT any](
+	reg *registry.Registry[ResourceT, ValueT],
+	config Config,
+) *Pool[ResourceT, ValueT] {
+	if config.Concurrency <= 0 {
+		panic("Worker pool must have at least one worker!")
+	}
+
+	return &Pool[ResourceT, ValueT]{
+		registry:  reg,
+		config:    config,
+		clock:     clock.OrReal(config.Clock),
+		requests:  make(chan *job[ValueT]),
+		done:      make(chan struct{}),
+		nextID:    &atomic.Uint64{},
+		restarts:  &atomic.Uint64{},
+		recycles:  &atomic.Uint64{},
+		wg:        &sync.WaitGroup{},
+		started:   &atomic.Bool{},
+		closeOnce: &sync.Once{},
+	}
+}
+
+// Start launches the worker processes.
+func (p *Pool[ResourceT, ValueT]) Start() {
+	if p.started.Swap(true) {
+		panic("attempt to start previously started procpool.Pool")
+	}
+
+	p.wg.Add(p.config.Concurrency)
+	for i := 0; i < p.config.Concurrency; i++ {
+		go p.worker()
+	}
+}
+
+// Close stops the pool, waiting for the running tasks to complete and the worker processes to exit.
+// It is safe to call Close multiple times, or before Start.
+func (p *Pool[ResourceT, ValueT]) Close() {
+	p.closeOnce.Do(p.close)
+}
+
+// close does not synchronize with [Pool.closeOnce].
+func (p *Pool[ResourceT, ValueT]) close() {
+	close(p.done)
+	p.wg.Wait()
+}
+
+// Restarts returns the number of worker processes which crashed or were killed, and were restarted.
+func (p *Pool[ResourceT, ValueT]) Restarts() uint64 {
+	return p.restarts.Load()
+}
+
+// Recycles returns the number of worker processes which were recycled, see [Config.MaxTasks] and [Config.MaxMemory].
+func (p *Pool[ResourceT, ValueT]) Recycles() uint64 {
+	return p.recycles.Load()
+}
+
+// Submit is a helper function to submit a [types.Task] to a [Pool] and wait for the result, like
+// [github.com/Izzette/go-safeconcurrency/workpool.Submit].
+// The task is serialized with the [registry.Registry] of the pool, and its type must be registered.
+// If the [context.Context] is canceled, the [context.Cause] is returned immediately, and the context of the task in
+// the worker process is canceled.
+// It returns [safeconcurrencyerrors.ErrPoolClosed] if the pool is closed.
+func Submit[ResourceT any, ValueT any](
+	ctx context.Context,
+	pool *Pool[ResourceT, ValueT],
+	tsk types.Task[ResourceT, ValueT],
+) (ValueT, error) {
+	var zero ValueT
+
+	// select is not deterministic, and may still send tasks even if the context has been canceled.
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return zero, err
+	}
+
+	envelope, err := pool.registry.Encode(tsk)
+	if err != nil {
+		//nolint:wrapcheck
+		return zero, err
+	}
+	j := &job[ValueT]{
+		ctx:      ctx,
+		id:       pool.nextID.Add(1),
+		envelope: envelope,
+		result:   make(chan jobResult[ValueT], 1),
+	}
+
+	select {
+	case <-ctx.Done():
+		//nolint:wrapcheck
+		return zero, context.Cause(ctx)
+	case <-pool.done:
+		return zero, safeconcurrencyerrors.ErrPoolClosed
+	case pool.requests <- j:
+	}
+
+	select {
+	case <-ctx.Done():
+		//nolint:wrapcheck
+		return zero, context.Cause(ctx)
+	case result := <-j.result:
+		return result.value, result.err
+	}
+}
+
+// worker is a goroutine managing a worker process, and sending it the submitted tasks.
+func (p *Pool[ResourceT, ValueT]) worker() {
+	defer p.wg.Done()
+
+	var proc *process[ValueT]
+	defer func() {
+		if proc != nil {
+			_ = proc.stop()
+		}
+	}()
+
+	var spawnErr error
+	for {
+		if proc == nil {
+			proc, spawnErr = p.spawn()
+		}
+
+		var j *job[ValueT]
+		select {
+		case <-p.done:
+			return
+		case j = <-p.requests:
+		}
+
+		if proc == nil {
+			j.reply(jobResult[ValueT]{err: spawnErr})
+
+			continue
+		}
+
+		resp, err := proc.execute(j, p.config.CancelGracePeriod, p.clock)
+		if err != nil {
+			// The exit status complements the failure, for example when the process crashed before responding.
+			err = errors.Join(err, proc.kill())
+			proc = nil
+			p.restarts.Add(1)
+			if cause := context.Cause(j.ctx); cause != nil {
+				// The task was canceled, and did not return within the grace period.
+				err = fmt.Errorf("%w: %w", cause, err)
+			} else {
+				err = fmt.Errorf("%w: %w", safeconcurrencyerrors.ErrWorkerCrashed, err)
+			}
+			j.reply(jobResult[ValueT]{err: err})
+
+			continue
+		}
+
+		result := jobResult[ValueT]{value: resp.Value}
+		if resp.Failed {
+			result.err = &TaskError{Message: resp.Err}
+		}
+		j.reply(result)
+
+		proc.tasks++
+		if (p.config.MaxTasks > 0 && proc.tasks >= p.config.MaxTasks) ||
+			(p.config.MaxMemory > 0 && resp.Memory >= p.config.MaxMemory) {
+			_ = proc.stop()
+			proc = nil
+			p.recycles.Add(1)
+		}
+	}
+}
+
+// spawn starts a worker process.
+func (p *Pool[ResourceT, ValueT]) spawn() (*process[ValueT], error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locating executable: %w", err)
+	}
+	args := p.config.Args
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	//nolint:gosec // The current executable is re-executed.
+	cmd := exec.Command(executable, args...)
+	cmd.Env = append(append(os.Environ(), p.config.Env...), workerEnv+"=1")
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating worker stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating worker stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting worker process: %w", err)
+	}
+
+	proc := &process[ValueT]{cmd: cmd, stdin: stdin, responses: make(chan response[ValueT], 1)}
+	go proc.read(stdout)
+
+	return proc, nil
+}
+
+// job is a task submitted to a [Pool].
+type job[ValueT any] struct {
+	//nolint:containedctx
+	ctx      context.Context
+	id       uint64
+	envelope registry.Envelope
+	result   chan jobResult[ValueT]
+}
+
+// reply publishes the result of the job without blocking.
+func (j *job[ValueT]) reply(result jobResult[ValueT]) {
+	j.result <- result
+}
+
+// jobResult is the outcome of a [job].
+type jobResult[ValueT any] struct {
+	value ValueT
+	err   error
+}
+
+// errWorkerExited is returned when the output of a worker process is closed while a task is executing.
+var errWorkerExited = errors.New("worker process exited")
+
+// process is a worker process.
+type process[ValueT any] struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	// responses is closed once the output of the process is closed, after readErr is set.
+	responses chan response[ValueT]
+	readErr   error
+	tasks     uint
+}
+
+// read publishes the responses of the worker process until its output is closed.
+func (proc *process[ValueT]) read(stdout io.Reader) {
+	defer close(proc.responses)
+
+	for {
+		var resp response[ValueT]
+		if err := readFrame(stdout, &resp); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errWorkerExited
+			}
+			proc.readErr = err
+
+			return
+		}
+		proc.responses <- resp
+	}
+}
+
+// execute sends the job to the worker process and waits for its response.
+// An error is returned if the worker process must be killed.
+func (proc *process[ValueT]) execute(
+	j *job[ValueT],
+	gracePeriod time.Duration,
+	clk types.Clock,
+) (response[ValueT], error) {
+	if err := writeFrame(proc.stdin, request{ID: j.id, Task: j.envelope}); err != nil {
+		return response[ValueT]{}, err
+	}
+
+	ctxDone := j.ctx.Done()
+	var timer types.Timer
+	var grace <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case resp, ok := <-proc.responses:
+			if !ok {
+				return response[ValueT]{}, proc.readErr
+			}
+			if resp.ID != j.id {
+				return response[ValueT]{}, fmt.Errorf("unexpected response for task %d instead of %d", resp.ID, j.id)
+			}
+
+			return resp, nil
+		case <-ctxDone:
+			ctxDone = nil
+			if err := writeFrame(proc.stdin, request{ID: j.id, Cancel: true}); err != nil {
+				return response[ValueT]{}, err
+			}
+			if gracePeriod > 0 {
+				timer = clk.NewTimer(gracePeriod)
+				grace = timer.C()
+			}
+		case <-grace:
+			return response[ValueT]{}, fmt.Errorf("task did not return within %v of its cancellation", gracePeriod)
+		}
+	}
+}
+
+// stop closes the input of the worker process, and waits for it to exit.
+func (proc *process[ValueT]) stop() error {
+	_ = proc.stdin.Close()
+
+	return proc.wait()
+}
+
+// kill kills the worker process, and waits for it to exit.
+func (proc *process[ValueT]) kill() error {
+	_ = proc.cmd.Process.Kill()
+	_ = proc.stdin.Close()
+
+	return proc.wait()
+}
+
+// wait waits for the output of the worker process to be closed, and for the process to exit.
+func (proc *process[ValueT]) wait() error {
+	for range proc.responses {
+		// Discard the responses which are no longer expected.
+	}
+
+	//nolint:wrapcheck
+	return proc.cmd.Wait()
+}
@@ -0,0 +1,235 @@
+package procpool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool/registry"
+)
+
+// resource is the resource of the worker processes.
+type resource struct {
+	greeting string
+}
+
+// greetTask greets its name, or fails if the name is empty.
+type greetTask struct {
+	Name string `json:"name"`
+}
+
+func (t *greetTask) Execute(_ context.Context, r *resource) (string, error) {
+	if t.Name == "" {
+		return "", errors.New("missing name")
+	}
+
+	return r.greeting + " " + t.Name, nil
+}
+
+// pidTask returns the pid of the worker process.
+type pidTask struct{}
+
+func (pidTask) Execute(context.Context, *resource) (string, error) {
+	return strconv.Itoa(os.Getpid()), nil
+}
+
+// crashTask exits the worker process.
+type crashTask struct{}
+
+func (crashTask) Execute(context.Context, *resource) (string, error) {
+	os.Exit(3)
+
+	return "", nil
+}
+
+// blockTask blocks until its context is canceled, or forever if it ignores cancellation.
+// It creates the Started file, if set, once executing.
+type blockTask struct {
+	IgnoreCancel bool   `json:"ignoreCancel"`
+	Started      string `json:"started"`
+}
+
+func (t *blockTask) Execute(ctx context.Context, _ *resource) (string, error) {
+	if t.Started != "" {
+		if err := os.WriteFile(t.Started, nil, 0o600); err != nil {
+			return "", err
+		}
+	}
+	if t.IgnoreCancel {
+		select {}
+	}
+	<-ctx.Done()
+
+	return "", context.Cause(ctx)
+}
+
+func newTestRegistry() *registry.Registry[*resource, string] {
+	r := registry.New[*resource, string]()
+	registry.Register[*greetTask](r, "greet", registry.JSON)
+	registry.Register[pidTask](r, "pid", registry.JSON)
+	registry.Register[crashTask](r, "crash", registry.JSON)
+	registry.Register[*blockTask](r, "block", registry.JSON)
+
+	return r
+}
+
+func TestMain(m *testing.M) {
+	ServeIfWorker(newTestRegistry(), &resource{greeting: "hello"})
+	os.Exit(m.Run())
+}
+
+// startPool starts a pool of the test binary, skipping the test in short mode as starting processes is slow.
+func startPool(t *testing.T, config Config) *Pool[*resource, string] {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping process pool test in short mode")
+	}
+
+	// The race detector otherwise delays the exit of the worker processes by a second.
+	config.Env = append(config.Env, "GORACE=atexit_sleep_ms=0")
+	p := New(newTestRegistry(), config)
+	p.Start()
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func TestSubmit(t *testing.T) {
+	p := startPool(t, Config{Concurrency: 2})
+
+	value, err := Submit[*resource, string](context.Background(), p, &greetTask{Name: "world"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != "hello world" {
+		t.Errorf("Expected %q, got %q", "hello world", value)
+	}
+
+	_, err = Submit[*resource, string](context.Background(), p, &greetTask{})
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.Message != "missing name" {
+		t.Errorf("Expected TaskError with message %q, got %v", "missing name", err)
+	}
+}
+
+func TestSubmitCrash(t *testing.T) {
+	p := startPool(t, Config{Concurrency: 1})
+
+	_, err := Submit[*resource, string](context.Background(), p, crashTask{})
+	if !errors.Is(err, safeconcurrencyerrors.ErrWorkerCrashed) {
+		t.Errorf("Expected ErrWorkerCrashed, got %v", err)
+	}
+	// The error reports both the failed read of the response and the exit status.
+	if !strings.Contains(err.Error(), "worker process exited") || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Expected the read error and the exit status, got %v", err)
+	}
+
+	// The worker process is restarted.
+	if _, err := Submit[*resource, string](context.Background(), p, &greetTask{Name: "again"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if p.Restarts() != 1 {
+		t.Errorf("Expected 1 restart, got %d", p.Restarts())
+	}
+}
+
+func TestRecycleMaxTasks(t *testing.T) {
+	p := startPool(t, Config{Concurrency: 1, MaxTasks: 2})
+
+	pids := make([]string, 4)
+	for i := range pids {
+		pid, err := Submit[*resource, string](context.Background(), p, pidTask{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		pids[i] = pid
+	}
+	if pids[0] != pids[1] || pids[2] != pids[3] || pids[1] == pids[2] {
+		t.Errorf("Expected the worker process to be recycled after 2 tasks, got pids %q", pids)
+	}
+	// The last worker process is recycled after its response is sent.
+	p.Close()
+	if p.Recycles() != 2 {
+		t.Errorf("Expected 2 recycles, got %d", p.Recycles())
+	}
+}
+
+func TestRecycleMaxMemory(t *testing.T) {
+	p := startPool(t, Config{Concurrency: 1, MaxMemory: 1})
+
+	first, _ := Submit[*resource, string](context.Background(), p, pidTask{})
+	second, _ := Submit[*resource, string](context.Background(), p, pidTask{})
+	if first == second {
+		t.Error("Expected the worker process to be recycled once it exceeds the memory threshold")
+	}
+}
+
+func TestSubmitCancel(t *testing.T) {
+	p := startPool(t, Config{Concurrency: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := Submit[*resource, string](ctx, p, &blockTask{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	// The task returns once canceled, and the worker process is reused.
+	if _, err := Submit[*resource, string](context.Background(), p, &greetTask{Name: "again"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if p.Restarts() != 0 {
+		t.Errorf("Expected no restart, got %d", p.Restarts())
+	}
+}
+
+func TestSubmitCancelGracePeriod(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	p := startPool(t, Config{Concurrency: 1, CancelGracePeriod: time.Second, Clock: fake})
+
+	started := filepath.Join(t.TempDir(), "started")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := Submit[*resource, string](ctx, p, &blockTask{IgnoreCancel: true, Started: started})
+		done <- err
+	}()
+	// The task must be executing in the worker process before it is canceled.
+	for {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	// The worker process is killed once the grace period elapses.
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	if _, err := Submit[*resource, string](context.Background(), p, &greetTask{Name: "again"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if p.Restarts() != 1 {
+		t.Errorf("Expected 1 restart, got %d", p.Restarts())
+	}
+}
+
+func TestSubmitClosed(t *testing.T) {
+	p := startPool(t, Config{Concurrency: 1})
+	p.Close()
+
+	_, err := Submit[*resource, string](context.Background(), p, pidTask{})
+	if !errors.Is(err, safeconcurrencyerrors.ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
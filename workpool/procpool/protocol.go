@@ -0,0 +1,73 @@
+package procpool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+
+	"github.com/Izzette/go-safeconcurrency/workpool/registry"
+)
+
+// maxFrameSize bounds the size of a frame, larger lengths are treated as a protocol error.
+const maxFrameSize = 1 << 30
+
+// request is a message sent by the parent to a worker process.
+type request struct {
+	ID uint64
+	// Cancel requests the cancellation of the task with the ID, instead of the execution of a new task.
+	Cancel bool
+	Task   registry.Envelope
+}
+
+// response is a message sent by a worker process to the parent once a task finished.
+type response[ValueT any] struct {
+	ID    uint64
+	Value ValueT
+	// Err is the message of the error returned by the task, if Failed.
+	Err    string
+	Failed bool
+	// Memory is the resident memory of the worker process after the task, in bytes.
+	Memory uint64
+}
+
+// writeFrame writes the value encoded as a standalone gob stream, prefixed with its length, in a single write.
+func writeFrame(w io.Writer, value any) error {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 4))
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return fmt.Errorf("encoding frame: %w", err)
+	}
+
+	frame := buf.Bytes()
+	binary.BigEndian.PutUint32(frame[:4], uint32(len(frame)-4))
+	if _, err := w.Write(frame); err != nil {
+		return fmt.Errorf("writing frame: %w", err)
+	}
+
+	return nil
+}
+
+// readFrame reads a frame written by [writeFrame], and decodes it into the value.
+func readFrame(r io.Reader, value any) error {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		//nolint:wrapcheck // io.EOF is expected when the pipe is closed.
+		return err
+	}
+
+	length := binary.BigEndian.Uint32(header[:])
+	if length > maxFrameSize {
+		return fmt.Errorf("reading frame: frame of %d bytes is too large", length)
+	}
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return fmt.Errorf("reading frame: %w", err)
+	}
+	if err := gob.NewDecoder(bytes.NewReader(frame)).Decode(value); err != nil {
+		return fmt.Errorf("decoding frame: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package procpool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/Izzette/go-safeconcurrency/workpool/registry"
+)
+
+// workerEnv is the environment variable set for the worker processes started by a [Pool].
+const workerEnv = "SAFECONCURRENCY_PROCPOOL_WORKER"
+
+// IsWorker returns true if the current process is a worker process started by a [Pool].
+func IsWorker() bool {
+	return os.Getenv(workerEnv) == "1"
+}
+
+// ServeIfWorker serves the tasks sent by the parent [Pool] and exits, if the current process is a worker process.
+// Otherwise, it returns immediately.
+// It must be called early in the main function of the program (or in TestMain), before any side effects which should
+// not be repeated in the worker processes, with the same [registry.Registry] as the [Pool].
+// The resource is passed to the tasks executed by the worker process.
+//
+// The standard output of the worker process is used to communicate with the parent, [os.Stdout] is therefore
+// redirected to [os.Stderr] while serving.
+// Tasks must not write to the standard output file descriptor directly.
+func ServeIfWorker[ResourceT any, ValueT any](reg *registry.Registry[ResourceT, ValueT], resource ResourceT) {
+	if !IsWorker() {
+		return
+	}
+
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	if err := serve(reg, resource, os.Stdin, out); err != nil {
+		os.Stderr.WriteString("procpool worker: " + err.Error() + "\n")
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// serve executes the tasks read from the input one at a time, and writes their responses to the output, until the
+// input is closed.
+func serve[ResourceT any, ValueT any](
+	reg *registry.Registry[ResourceT, ValueT],
+	resource ResourceT,
+	in io.Reader,
+	out io.Writer,
+) error {
+	tasks := make(chan runningTask)
+	readErr := make(chan error, 1)
+
+	// current is the task being executed, it is protected by mu.
+	// It is set by the reader, so that a cancellation following the task immediately is not missed.
+	var mu sync.Mutex
+	var current *runningTask
+
+	go func() {
+		defer close(tasks)
+		for {
+			var req request
+			if err := readFrame(in, &req); err != nil {
+				if !errors.Is(err, io.EOF) {
+					readErr <- err
+				}
+
+				mu.Lock()
+				if current != nil {
+					current.cancel()
+				}
+				mu.Unlock()
+
+				return
+			}
+
+			if !req.Cancel {
+				ctx, cancel := context.WithCancel(context.Background())
+				tsk := &runningTask{req: req, ctx: ctx, cancel: cancel}
+				mu.Lock()
+				current = tsk
+				mu.Unlock()
+				tasks <- *tsk
+
+				continue
+			}
+			mu.Lock()
+			if current != nil && current.req.ID == req.ID {
+				current.cancel()
+			}
+			mu.Unlock()
+		}
+	}()
+
+	for tsk := range tasks {
+		resp := execute(tsk.ctx, reg, resource, tsk.req)
+		tsk.cancel()
+
+		if err := writeFrame(out, resp); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case err := <-readErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+// runningTask is a task received by a worker process.
+type runningTask struct {
+	req request
+	//nolint:containedctx
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// execute decodes and executes the task of the request.
+func execute[ResourceT any, ValueT any](
+	ctx context.Context,
+	reg *registry.Registry[ResourceT, ValueT],
+	resource ResourceT,
+	req request,
+) response[ValueT] {
+	resp := response[ValueT]{ID: req.ID}
+
+	task, err := reg.Decode(req.Task)
+	if err == nil {
+		resp.Value, err = task.Execute(ctx, resource)
+	}
+	if err != nil {
+		resp.Failed = true
+		resp.Err = err.Error()
+	}
+	resp.Memory = residentMemory()
+
+	return resp
+}
+
+// runtimeMemory returns the memory obtained from the operating system by the Go runtime, in bytes.
+func runtimeMemory() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return stats.Sys
+}
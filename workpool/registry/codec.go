@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec serializes the tasks of a type registered in a [Registry].
+type Codec interface {
+	// Marshal serializes the value.
+	Marshal(any) ([]byte, error)
+
+	// Unmarshal deserializes the data into the value, which is a pointer.
+	Unmarshal([]byte, any) error
+}
+
+// JSON is a [Codec] using [encoding/json].
+//
+//nolint:gochecknoglobals
+var JSON Codec = jsonCodec{}
+
+// Gob is a [Codec] using [encoding/gob].
+// Each value is encoded as a standalone gob stream, including its type description.
+//
+//nolint:gochecknoglobals
+var Gob Codec = gobCodec{}
+
+// jsonCodec implements [Codec] using [encoding/json].
+type jsonCodec struct{}
+
+// Marshal implements [Codec.Marshal].
+func (jsonCodec) Marshal(value any) ([]byte, error) {
+	//nolint:wrapcheck
+	return json.Marshal(value)
+}
+
+// Unmarshal implements [Codec.Unmarshal].
+func (jsonCodec) Unmarshal(data []byte, value any) error {
+	//nolint:wrapcheck
+	return json.Unmarshal(data, value)
+}
+
+// gobCodec implements [Codec] using [encoding/gob].
+type gobCodec struct{}
+
+// Marshal implements [Codec.Marshal].
+func (gobCodec) Marshal(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal implements [Codec.Unmarshal].
+func (gobCodec) Unmarshal(data []byte, value any) error {
+	//nolint:wrapcheck
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(value)
+}
@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// Envelope is a serialized [types.Task], as produced by [Registry.Encode].
+// It may itself be serialized with [encoding/json] or [encoding/gob].
+type Envelope struct {
+	// Name is the name under which the type of the task is registered.
+	Name string `json:"name"`
+	// Data is the task serialized by the [Codec] of its type.
+	Data []byte `json:"data"`
+}
+
+// Registry maps the names of serializable [types.Task] types to their [Codec], so that tasks may be persisted or sent
+// to another process and executed there.
+// Use [Register] to register task types.
+// It is safe for concurrent use.
+type Registry[ResourceT any, ValueT any] struct {
+	mu     sync.RWMutex
+	byName map[string]*registeredType[ResourceT, ValueT]
+	byType map[reflect.Type]*registeredType[ResourceT, ValueT]
+}
+
+// New creates an empty [Registry].
+func New[ResourceT any, ValueT any]() *Registry[ResourceT, ValueT] {
+	return &Registry[ResourceT, ValueT]{
+		byName: make(map[string]*registeredType[ResourceT, ValueT]),
+		byType: make(map[reflect.Type]*registeredType[ResourceT, ValueT]),
+	}
+}
+
+// Register registers the TaskT type under the name, serialized with the [Codec].
+// TaskT may be a pointer type, in which case a new value is allocated for each decoded task.
+// The name is persisted along with the tasks, and must not change between the processes encoding and decoding them.
+//
+// It panics if the name or the type is already registered.
+func Register[TaskT types.Task[ResourceT, ValueT], ResourceT any, ValueT any](
+	r *Registry[ResourceT, ValueT],
+	name string,
+	codec Codec,
+) {
+	typ := reflect.TypeOf((*TaskT)(nil)).Elem()
+	registered := &registeredType[ResourceT, ValueT]{
+		name:  name,
+		codec: codec,
+		decode: func(data []byte) (types.Task[ResourceT, ValueT], error) {
+			var task TaskT
+			if typ.Kind() == reflect.Pointer {
+				//nolint:forcetypeassert
+				task = reflect.New(typ.Elem()).Interface().(TaskT)
+				if err := codec.Unmarshal(data, task); err != nil {
+					//nolint:wrapcheck
+					return nil, err
+				}
+
+				return task, nil
+			}
+			if err := codec.Unmarshal(data, &task); err != nil {
+				//nolint:wrapcheck
+				return nil, err
+			}
+
+			return task, nil
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byName[name]; ok {
+		panic(fmt.Sprintf("task name %q is already registered", name))
+	}
+	if _, ok := r.byType[typ]; ok {
+		panic(fmt.Sprintf("task type %v is already registered", typ))
+	}
+	r.byName[name] = registered
+	r.byType[typ] = registered
+}
+
+// Encode serializes the [types.Task] with the [Codec] of its registered type.
+// It returns an error wrapping [safeconcurrencyerrors.ErrUnregisteredTask] if the type of the task is not registered.
+func (r *Registry[ResourceT, ValueT]) Encode(task types.Task[ResourceT, ValueT]) (Envelope, error) {
+	typ := reflect.TypeOf(task)
+	r.mu.RLock()
+	registered, ok := r.byType[typ]
+	r.mu.RUnlock()
+	if !ok {
+		return Envelope{}, fmt.Errorf("%w: type %v", safeconcurrencyerrors.ErrUnregisteredTask, typ)
+	}
+
+	data, err := registered.codec.Marshal(task)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("encoding task %q: %w", registered.name, err)
+	}
+
+	return Envelope{Name: registered.name, Data: data}, nil
+}
+
+// Decode deserializes the [types.Task] in the [Envelope].
+// It returns an error wrapping [safeconcurrencyerrors.ErrUnregisteredTask] if the name of the envelope is not
+// registered.
+func (r *Registry[ResourceT, ValueT]) Decode(envelope Envelope) (types.Task[ResourceT, ValueT], error) {
+	r.mu.RLock()
+	registered, ok := r.byName[envelope.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: name %q", safeconcurrencyerrors.ErrUnregisteredTask, envelope.Name)
+	}
+
+	task, err := registered.decode(envelope.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding task %q: %w", envelope.Name, err)
+	}
+
+	return task, nil
+}
+
+// registeredType is a task type registered in a [Registry].
+type registeredType[ResourceT any, ValueT any] struct {
+	name   string
+	codec  Codec
+	decode func([]byte) (types.Task[ResourceT, ValueT], error)
+}
@@ -0,0 +1,101 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+)
+
+type greetTask struct {
+	Name string `json:"name"`
+}
+
+func (t *greetTask) Execute(context.Context, any) (string, error) {
+	return "hello " + t.Name, nil
+}
+
+type addTask struct {
+	A, B int
+}
+
+func (t addTask) Execute(context.Context, any) (string, error) {
+	return "", nil
+}
+
+type unregisteredTask struct{}
+
+func (unregisteredTask) Execute(context.Context, any) (string, error) {
+	return "", nil
+}
+
+func newTestRegistry() *Registry[any, string] {
+	r := New[any, string]()
+	Register[*greetTask](r, "greet", JSON)
+	Register[addTask](r, "add", Gob)
+
+	return r
+}
+
+func TestRegistryRoundTrip(t *testing.T) {
+	r := newTestRegistry()
+
+	envelope, err := r.Encode(&greetTask{Name: "world"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if envelope.Name != "greet" || string(envelope.Data) != `{"name":"world"}` {
+		t.Errorf("Unexpected envelope %+v", envelope)
+	}
+	task, err := r.Decode(envelope)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val, _ := task.Execute(context.Background(), nil); val != "hello world" {
+		t.Errorf("Expected %q, got %q", "hello world", val)
+	}
+
+	envelope, err = r.Encode(addTask{A: 1, B: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	task, err = r.Decode(envelope)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if task != (addTask{A: 1, B: 2}) {
+		t.Errorf("Expected addTask{1 2}, got %+v", task)
+	}
+}
+
+func TestRegistryUnregistered(t *testing.T) {
+	r := newTestRegistry()
+
+	if _, err := r.Encode(unregisteredTask{}); !errors.Is(err, safeconcurrencyerrors.ErrUnregisteredTask) {
+		t.Errorf("Expected ErrUnregisteredTask, got %v", err)
+	}
+	if _, err := r.Decode(Envelope{Name: "unknown"}); !errors.Is(err, safeconcurrencyerrors.ErrUnregisteredTask) {
+		t.Errorf("Expected ErrUnregisteredTask, got %v", err)
+	}
+	if _, err := r.Decode(Envelope{Name: "greet", Data: []byte("not json")}); err == nil {
+		t.Error("Expected decoding error")
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	r := newTestRegistry()
+	for _, register := range []func(){
+		func() { Register[addTask](r, "greet", JSON) },
+		func() { Register[*greetTask](r, "other", JSON) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected panic")
+				}
+			}()
+			register()
+		}()
+	}
+}
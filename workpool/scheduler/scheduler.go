@@ -0,0 +1,387 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool/task"
+)
+
+// ClosePolicy selects what happens to the pending tasks when a [Scheduler] is closed.
+type ClosePolicy int
+
+const (
+	// RejectPending completes the pending tasks with [safeconcurrencyerrors.ErrSchedulerClosed] without executing
+	// them.
+	RejectPending ClosePolicy = iota
+
+	// DrainPending submits all the pending tasks to the [types.WorkerPool] immediately, without waiting for their
+	// scheduled time.
+	DrainPending
+)
+
+// Config configures a [Scheduler].
+type Config struct {
+	// Clock is used to determine when tasks are due, the [clock.Real] clock is used if nil.
+	Clock types.Clock
+
+	// OnClose selects what happens to the pending tasks when the [Scheduler] is closed, [RejectPending] by default.
+	OnClose ClosePolicy
+}
+
+// Scheduler submits tasks to a [types.WorkerPool] at a specific time, or after a delay.
+// A single goroutine and timer are used for all the pending tasks, which are kept in a heap ordered by their scheduled
+// time.
+// Use [SubmitAt] or [SubmitAfter] to schedule tasks.
+//
+// The [types.WorkerPool] must remain open until the [Scheduler] is closed.
+type Scheduler[ResourceT any] struct {
+	pool    types.WorkerPool[ResourceT]
+	clock   types.Clock
+	onClose ClosePolicy
+
+	// mu protects entries, sequence, and closed.
+	mu       sync.Mutex
+	entries  entryHeap[ResourceT]
+	sequence uint64
+	closed   bool
+
+	// wake is signaled when the earliest entry changes or the scheduler is closed.
+	wake      chan struct{}
+	done      chan struct{}
+	started   *atomic.Bool
+	closeOnce *sync.Once
+}
+
+// New creates (but does not start) a [Scheduler] submitting tasks to the [types.WorkerPool].
+func New[ResourceT any](pool types.WorkerPool[ResourceT], config Config) *Scheduler[ResourceT] {
+	return &Scheduler[ResourceT]{
+		pool:      pool,
+		clock:     clock.OrReal(config.Clock),
+		onClose:   config.OnClose,
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		started:   &atomic.Bool{},
+		closeOnce: &sync.Once{},
+	}
+}
+
+// Start launches the goroutine submitting the tasks when they are due.
+func (s *Scheduler[ResourceT]) Start() {
+	if s.started.Swap(true) {
+		panic("attempt to start previously started scheduler.Scheduler")
+	}
+
+	go s.run()
+}
+
+// Close stops the [Scheduler], handling the pending tasks according to the [ClosePolicy].
+// It waits for the pending tasks to be rejected or submitted, but not for them to complete.
+// It is safe to call Close multiple times, or before Start.
+func (s *Scheduler[ResourceT]) Close() {
+	s.closeOnce.Do(s.close)
+}
+
+// Pending returns the number of tasks waiting for their scheduled time.
+func (s *Scheduler[ResourceT]) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// close marks the scheduler as closed and waits for the pending tasks to be handled.
+// It does not synchronize with [Scheduler.closeOnce].
+func (s *Scheduler[ResourceT]) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	if !s.started.Load() {
+		// Nobody else will handle the pending tasks.
+		s.closePending()
+
+		return
+	}
+
+	s.signal()
+	<-s.done
+}
+
+// SubmitAt schedules the [types.Task] to be submitted to the [types.WorkerPool] of the [Scheduler] at the provided
+// time, and returns a [Handle] to the result.
+// Tasks scheduled in the past are submitted immediately.
+//
+// # Context
+//
+// The [context.Context] is passed to the task when it is executed.
+// If it is canceled before the task is submitted, the task will be completed with the [context.Cause] when it becomes
+// due, without being submitted.
+//
+// # Order
+//
+// Tasks are submitted in order of their scheduled time, tasks scheduled at the same time are submitted in the order
+// they were scheduled.
+func SubmitAt[ResourceT any, ValueT any](
+	ctx context.Context,
+	s *Scheduler[ResourceT],
+	at time.Time,
+	tsk types.Task[ResourceT, ValueT],
+) *Handle[ValueT] {
+	valuelessTask, taskResult := task.Wrap[ResourceT, ValueT](ctx, tsk)
+	e := &entry[ResourceT]{ctx: ctx, at: at, task: valuelessTask, index: -1}
+	handle := &Handle[ValueT]{TaskResult: taskResult, at: at, cancel: func() bool { return s.cancel(e) }}
+
+	s.schedule(e)
+
+	return handle
+}
+
+// SubmitAfter schedules the [types.Task] to be submitted to the [types.WorkerPool] of the [Scheduler] after the
+// delay.
+// It is equivalent to calling [SubmitAt] with the current time of the [Config.Clock] plus the delay.
+func SubmitAfter[ResourceT any, ValueT any](
+	ctx context.Context,
+	s *Scheduler[ResourceT],
+	delay time.Duration,
+	tsk types.Task[ResourceT, ValueT],
+) *Handle[ValueT] {
+	return SubmitAt(ctx, s, s.clock.Now().Add(delay), tsk)
+}
+
+// Handle is returned by [SubmitAt] and [SubmitAfter] to access the result of a scheduled task.
+// The embedded [types.TaskResult] is completed once the task has been executed, rejected, or canceled.
+type Handle[ValueT any] struct {
+	types.TaskResult[ValueT]
+	at     time.Time
+	cancel func() bool
+}
+
+// At returns the time at which the task is scheduled.
+func (h *Handle[ValueT]) At() time.Time {
+	return h.at
+}
+
+// Cancel removes the task from the [Scheduler] if it has not yet been submitted, completing it with
+// [context.Canceled].
+// It returns true if the task was removed, or false if it has already been submitted or completed.
+// To stop a task which has already been submitted, cancel its [context.Context].
+func (h *Handle[ValueT]) Cancel() bool {
+	return h.cancel()
+}
+
+// Wait blocks until the task is completed, or the [context.Context] is canceled, and returns the result of the task.
+func (h *Handle[ValueT]) Wait(ctx context.Context) (ValueT, error) {
+	var zero ValueT
+
+	select {
+	case <-ctx.Done():
+		//nolint:wrapcheck
+		return zero, context.Cause(ctx)
+	case result := <-h.Results():
+		// We must drain the results channel to ensure that the err is set correctly.
+		if err := h.Drain(); err != nil {
+			//nolint:wrapcheck
+			return zero, err
+		}
+
+		return result, nil
+	}
+}
+
+// schedule adds the entry to the heap, or rejects it if the scheduler is closed.
+func (s *Scheduler[ResourceT]) schedule(e *entry[ResourceT]) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		e.reject(safeconcurrencyerrors.ErrSchedulerClosed)
+
+		return
+	}
+
+	e.sequence = s.sequence
+	s.sequence++
+	heap.Push(&s.entries, e)
+	earliest := s.entries[0] == e
+	s.mu.Unlock()
+
+	if earliest {
+		s.signal()
+	}
+}
+
+// cancel removes the entry from the heap and rejects it, returning false if it is no longer pending.
+func (s *Scheduler[ResourceT]) cancel(e *entry[ResourceT]) bool {
+	s.mu.Lock()
+	if e.index < 0 {
+		s.mu.Unlock()
+
+		return false
+	}
+	heap.Remove(&s.entries, e.index)
+	s.mu.Unlock()
+
+	e.reject(context.Canceled)
+
+	return true
+}
+
+// signal wakes the scheduler goroutine without blocking.
+func (s *Scheduler[ResourceT]) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run is the scheduler goroutine launched by [Scheduler.Start].
+func (s *Scheduler[ResourceT]) run() {
+	defer close(s.done)
+
+	timer := s.clock.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			s.closePending()
+
+			return
+		}
+		due := s.popDue(s.clock.Now())
+		var wait <-chan time.Time
+		if len(s.entries) > 0 {
+			timer.Reset(s.entries[0].at.Sub(s.clock.Now()))
+			wait = timer.C()
+		} else {
+			timer.Stop()
+		}
+		s.mu.Unlock()
+
+		for _, e := range due {
+			s.submit(e)
+		}
+		if len(due) > 0 {
+			// Submitting may have taken a while, more entries may be due.
+			continue
+		}
+
+		select {
+		case <-wait:
+		case <-s.wake:
+		}
+	}
+}
+
+// popDue removes the entries which are due at the provided time from the heap, in order.
+// The caller must hold the lock.
+func (s *Scheduler[ResourceT]) popDue(now time.Time) []*entry[ResourceT] {
+	var due []*entry[ResourceT]
+	for len(s.entries) > 0 && !s.entries[0].at.After(now) {
+		//nolint:forcetypeassert
+		due = append(due, heap.Pop(&s.entries).(*entry[ResourceT]))
+	}
+
+	return due
+}
+
+// closePending handles all the entries remaining in the heap according to the close policy.
+func (s *Scheduler[ResourceT]) closePending() {
+	s.mu.Lock()
+	pending := s.popDue(maxTime)
+	s.mu.Unlock()
+
+	for _, e := range pending {
+		if s.onClose == DrainPending {
+			s.submit(e)
+		} else {
+			e.reject(safeconcurrencyerrors.ErrSchedulerClosed)
+		}
+	}
+}
+
+// submit sends the task of the entry to the pool, rejecting it if its context is canceled first.
+func (s *Scheduler[ResourceT]) submit(e *entry[ResourceT]) {
+	// select is not deterministic, and may still send tasks even if the context has been canceled.
+	if err := context.Cause(e.ctx); err != nil {
+		e.reject(err)
+
+		return
+	}
+
+	select {
+	case <-e.ctx.Done():
+		e.reject(context.Cause(e.ctx))
+	case s.pool.Requests() <- e.task:
+	}
+}
+
+// maxTime is later than any time a task may be scheduled at.
+var maxTime = time.Unix(1<<62, 0)
+
+// entry is a task waiting in the heap of the [Scheduler].
+type entry[ResourceT any] struct {
+	//nolint:containedctx
+	ctx      context.Context
+	at       time.Time
+	sequence uint64
+	task     types.ValuelessTask[ResourceT]
+	// index is the position of the entry in the heap, or -1 if it is no longer in the heap.
+	index int
+}
+
+// reject completes the task of the entry without executing it.
+func (e *entry[ResourceT]) reject(err error) {
+	//nolint:forcetypeassert // task.Wrap always returns a types.RejectableTask.
+	e.task.(types.RejectableTask).Reject(err)
+}
+
+// entryHeap implements [heap.Interface] ordering entries by scheduled time and sequence.
+type entryHeap[ResourceT any] []*entry[ResourceT]
+
+// Len implements [heap.Interface.Len].
+func (h entryHeap[ResourceT]) Len() int {
+	return len(h)
+}
+
+// Less implements [heap.Interface.Less].
+func (h entryHeap[ResourceT]) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].sequence < h[j].sequence
+	}
+
+	return h[i].at.Before(h[j].at)
+}
+
+// Swap implements [heap.Interface.Swap].
+func (h entryHeap[ResourceT]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push implements [heap.Interface.Push].
+func (h *entryHeap[ResourceT]) Push(x any) {
+	//nolint:forcetypeassert
+	e := x.(*entry[ResourceT])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+// Pop implements [heap.Interface.Pop].
+func (h *entryHeap[ResourceT]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+
+	return e
+}
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/safeconcurrencyerrors"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/workpool"
+)
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type recordingTask struct {
+	name  string
+	mu    *sync.Mutex
+	order *[]string
+}
+
+func (t *recordingTask) Execute(context.Context, any) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*t.order = append(*t.order, t.name)
+
+	return t.name, nil
+}
+
+func newTestScheduler(t *testing.T, onClose ClosePolicy) (*Scheduler[any], *clock.Fake, func()) {
+	t.Helper()
+
+	p := workpool.New[any](nil, 1)
+	p.Start()
+	fake := clock.NewFake(epoch)
+	s := New[any](p, Config{Clock: fake, OnClose: onClose})
+	s.Start()
+
+	return s, fake, func() {
+		s.Close()
+		p.Close()
+	}
+}
+
+func TestSubmitAfterOrder(t *testing.T) {
+	s, fake, cleanup := newTestScheduler(t, RejectPending)
+	defer cleanup()
+
+	ctx := context.Background()
+	mu := &sync.Mutex{}
+	order := make([]string, 0, 3)
+	handles := []*Handle[string]{
+		SubmitAfter[any, string](ctx, s, 3*time.Second, &recordingTask{name: "third", mu: mu, order: &order}),
+		SubmitAfter[any, string](ctx, s, time.Second, &recordingTask{name: "first", mu: mu, order: &order}),
+		SubmitAt[any, string](ctx, s, epoch.Add(2*time.Second), &recordingTask{name: "second", mu: mu, order: &order}),
+	}
+	if s.Pending() != 3 {
+		t.Errorf("Expected 3 pending tasks, got %d", s.Pending())
+	}
+	if !handles[1].At().Equal(epoch.Add(time.Second)) {
+		t.Errorf("Expected task to be scheduled at %v, got %v", epoch.Add(time.Second), handles[1].At())
+	}
+
+	fake.Advance(3 * time.Second)
+	for _, handle := range handles {
+		if _, err := handle.Wait(ctx); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	expected := []string{"first", "second", "third"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected %v, got %v", expected, order)
+	}
+}
+
+func TestSubmitAfterFiresOnTime(t *testing.T) {
+	s, fake, cleanup := newTestScheduler(t, RejectPending)
+	defer cleanup()
+
+	ctx := context.Background()
+	mu := &sync.Mutex{}
+	order := make([]string, 0, 1)
+	handle := SubmitAfter[any, string](ctx, s, time.Minute, &recordingTask{name: "task", mu: mu, order: &order})
+
+	// Wait for the scheduler goroutine to arm the timer for the task.
+	fake.WaitForTimers(1)
+	fake.Advance(time.Minute)
+	val, err := handle.Wait(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val != "task" {
+		t.Errorf("Expected %q, got %q", "task", val)
+	}
+	if handle.Cancel() {
+		t.Error("Expected Cancel to return false for a submitted task")
+	}
+}
+
+func TestHandleCancel(t *testing.T) {
+	s, fake, cleanup := newTestScheduler(t, RejectPending)
+	defer cleanup()
+
+	ctx := context.Background()
+	mu := &sync.Mutex{}
+	order := make([]string, 0)
+	handle := SubmitAfter[any, string](ctx, s, time.Second, &recordingTask{name: "task", mu: mu, order: &order})
+
+	fake.Advance(500 * time.Millisecond)
+	if !handle.Cancel() {
+		t.Error("Expected Cancel to return true for a pending task")
+	}
+	if _, err := handle.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if s.Pending() != 0 {
+		t.Errorf("Expected no pending tasks, got %d", s.Pending())
+	}
+}
+
+func TestSubmitContextCanceled(t *testing.T) {
+	s, fake, cleanup := newTestScheduler(t, RejectPending)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mu := &sync.Mutex{}
+	order := make([]string, 0)
+	handle := SubmitAfter[any, string](ctx, s, time.Second, &recordingTask{name: "task", mu: mu, order: &order})
+	cancel()
+
+	fake.Advance(time.Second)
+	if err := handle.Drain(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(order) != 0 {
+		t.Errorf("Expected task not to be executed, got %v", order)
+	}
+}
+
+func TestCloseRejectPending(t *testing.T) {
+	s, _, cleanup := newTestScheduler(t, RejectPending)
+	defer cleanup()
+
+	ctx := context.Background()
+	mu := &sync.Mutex{}
+	order := make([]string, 0)
+	handle := SubmitAfter[any, string](ctx, s, time.Hour, &recordingTask{name: "task", mu: mu, order: &order})
+	s.Close()
+
+	if _, err := handle.Wait(ctx); !errors.Is(err, safeconcurrencyerrors.ErrSchedulerClosed) {
+		t.Errorf("Expected ErrSchedulerClosed, got %v", err)
+	}
+
+	// Tasks submitted after close are rejected immediately.
+	handle = SubmitAfter[any, string](ctx, s, 0, &recordingTask{name: "task", mu: mu, order: &order})
+	if err := handle.Drain(); !errors.Is(err, safeconcurrencyerrors.ErrSchedulerClosed) {
+		t.Errorf("Expected ErrSchedulerClosed, got %v", err)
+	}
+}
+
+func TestCloseDrainPending(t *testing.T) {
+	s, _, cleanup := newTestScheduler(t, DrainPending)
+	defer cleanup()
+
+	ctx := context.Background()
+	mu := &sync.Mutex{}
+	order := make([]string, 0, 2)
+	second := SubmitAfter[any, string](ctx, s, 2*time.Hour, &recordingTask{name: "second", mu: mu, order: &order})
+	first := SubmitAfter[any, string](ctx, s, time.Hour, &recordingTask{name: "first", mu: mu, order: &order})
+	s.Close()
+
+	for _, handle := range []*Handle[string]{first, second} {
+		if _, err := handle.Wait(ctx); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	expected := []string{"first", "second"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected %v, got %v", expected, order)
+	}
+}
+
+func TestCloseBeforeStart(t *testing.T) {
+	p := workpool.New[any](nil, 1)
+	defer p.Close()
+	s := New[any](p, Config{})
+
+	mu := &sync.Mutex{}
+	order := make([]string, 0)
+	handle := SubmitAfter[any, string](context.Background(), s, time.Hour, &recordingTask{mu: mu, order: &order})
+	s.Close()
+	if err := handle.Drain(); !errors.Is(err, safeconcurrencyerrors.ErrSchedulerClosed) {
+		t.Errorf("Expected ErrSchedulerClosed, got %v", err)
+	}
+}
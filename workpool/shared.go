@@ -0,0 +1,241 @@
+package workpool
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+	"github.com/Izzette/go-safeconcurrency/internal/safeconcurrencycontext"
+)
+
+// SharedConfig configures a [SharedGroup].
+type SharedConfig struct {
+	// TTL is the duration for which successful results are cached, results are not cached if it is 0.
+	TTL time.Duration
+
+	// Clock is used to expire cached results, the [clock.Real] clock is used if nil.
+	Clock types.Clock
+}
+
+// SharedGroup deduplicates identical [types.Task] submitted concurrently to a [types.WorkerPool].
+// Concurrent callers submitting a task with the same key share a single execution and its result.
+// See [SharedGroup.Submit] for details.
+type SharedGroup[ResourceT any, ValueT any] struct {
+	pool  types.WorkerPool[ResourceT]
+	ttl   time.Duration
+	clock types.Clock
+
+	// mu protects calls and cache.
+	mu    sync.Mutex
+	calls map[string]*sharedCall[ValueT]
+	cache map[string]cachedResult[ValueT]
+}
+
+// NewSharedGroup creates a new [SharedGroup] submitting tasks to the [types.WorkerPool].
+func NewSharedGroup[ResourceT any, ValueT any](
+	pool types.WorkerPool[ResourceT],
+	config SharedConfig,
+) *SharedGroup[ResourceT, ValueT] {
+	return &SharedGroup[ResourceT, ValueT]{
+		pool:  pool,
+		ttl:   config.TTL,
+		clock: clock.OrReal(config.Clock),
+		calls: make(map[string]*sharedCall[ValueT]),
+		cache: make(map[string]cachedResult[ValueT]),
+	}
+}
+
+// Submit submits the [types.Task] to the [types.WorkerPool] and waits for the result, unless a task with the same key
+// is already in flight, in which case the result of that execution is shared.
+// If a cached result for the key has not yet expired, it is returned without submitting the task.
+//
+// # Context
+//
+// The task is executed with a [context.Context] carrying the values of the context of the first caller, but which is
+// only canceled once every caller sharing the execution has given up.
+// A caller whose context is canceled returns immediately with the [context.Cause], the execution continues for the
+// remaining callers.
+// Once all callers have given up, the next caller with the same key starts a new execution.
+//
+// # Warning
+//
+// Tasks sharing a key must be interchangeable, only the task of the first caller is executed.
+func (g *SharedGroup[ResourceT, ValueT]) Submit(
+	ctx context.Context,
+	key string,
+	tsk types.Task[ResourceT, ValueT],
+) (ValueT, error) {
+	var zero ValueT
+
+	if err := context.Cause(ctx); err != nil {
+		//nolint:wrapcheck
+		return zero, err
+	}
+
+	g.mu.Lock()
+	if cached, ok := g.cache[key]; ok {
+		if g.clock.Now().Before(cached.expires) {
+			g.mu.Unlock()
+
+			return cached.value, nil
+		}
+		delete(g.cache, key)
+	}
+
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancelCause(safeconcurrencycontext.WithoutCancel(ctx))
+		call = &sharedCall[ValueT]{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+		go g.execute(callCtx, key, call, tsk)
+	}
+	call.refs++
+	g.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		g.release(key, call)
+
+		//nolint:wrapcheck
+		return zero, context.Cause(ctx)
+	case <-call.done:
+		return call.value, call.err
+	}
+}
+
+// Forget removes the cached result for the key, if any.
+// Executions in flight are unaffected.
+func (g *SharedGroup[ResourceT, ValueT]) Forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.cache, key)
+}
+
+// execute runs the shared call and publishes its result.
+func (g *SharedGroup[ResourceT, ValueT]) execute(
+	ctx context.Context,
+	key string,
+	call *sharedCall[ValueT],
+	tsk types.Task[ResourceT, ValueT],
+) {
+	defer close(call.done)
+	defer call.cancel(context.Canceled)
+
+	value, err := Submit(ctx, g.pool, tsk)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	call.value, call.err = value, err
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	if err == nil && g.ttl > 0 {
+		g.cache[key] = cachedResult[ValueT]{value: value, expires: g.clock.Now().Add(g.ttl)}
+	}
+}
+
+// release records that a caller has given up on the shared call, canceling it if it was the last one.
+func (g *SharedGroup[ResourceT, ValueT]) release(key string, call *sharedCall[ValueT]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	call.refs--
+	if call.refs > 0 {
+		return
+	}
+
+	call.cancel(context.Canceled)
+	// New callers must not join a canceled call.
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// sharedCall is an execution shared between callers of [SharedGroup.Submit].
+type sharedCall[ValueT any] struct {
+	// done is closed once value and err are set.
+	done   chan struct{}
+	value  ValueT
+	err    error
+	cancel context.CancelCauseFunc
+	// refs is the number of callers waiting for the result, it is protected by the mutex of the group.
+	refs int
+}
+
+// cachedResult is a successful result cached by a [SharedGroup].
+type cachedResult[ValueT any] struct {
+	value   ValueT
+	expires time.Time
+}
+
+// sharedGroupKey identifies the [SharedGroup] used by [SubmitShared] for a pool and value type.
+type sharedGroupKey struct {
+	pool any
+	// value is a nil pointer to the value type, pointers to distinct types are never equal.
+	value any
+}
+
+// sharedGroup is a [SharedGroup] used by [SubmitShared], along with the number of callers using it.
+type sharedGroup struct {
+	group any
+	users int
+}
+
+// sharedGroupsMu protects sharedGroups.
+//
+//nolint:gochecknoglobals
+var sharedGroupsMu sync.Mutex
+
+// sharedGroups holds the [SharedGroup] instances used by [SubmitShared] while they have callers, so that the pools are
+// not retained once they are no longer used.
+//
+//nolint:gochecknoglobals
+var sharedGroups = make(map[sharedGroupKey]*sharedGroup)
+
+// SubmitShared is a helper function to submit a [types.Task] to a [types.WorkerPool] and wait for the result, sharing
+// a single execution between concurrent callers using the same key.
+// It uses a [SharedGroup] without caching, which is shared by all callers using the same pool and value type, and
+// forgotten once no caller is using it.
+// If the pool is not comparable, a new [SharedGroup] is used for each call, and the executions are not shared.
+// All the pools created by this package are comparable.
+// See [SharedGroup.Submit] for details, and use [NewSharedGroup] directly to cache results.
+func SubmitShared[ResourceT any, ValueT any](
+	ctx context.Context,
+	pool types.WorkerPool[ResourceT],
+	key string,
+	tsk types.Task[ResourceT, ValueT],
+) (ValueT, error) {
+	if !reflect.ValueOf(pool).Comparable() {
+		return NewSharedGroup[ResourceT, ValueT](pool, SharedConfig{}).Submit(ctx, key, tsk)
+	}
+
+	groupKey := sharedGroupKey{pool: pool, value: (*ValueT)(nil)}
+	sharedGroupsMu.Lock()
+	entry, ok := sharedGroups[groupKey]
+	if !ok {
+		entry = &sharedGroup{group: NewSharedGroup[ResourceT, ValueT](pool, SharedConfig{})}
+		sharedGroups[groupKey] = entry
+	}
+	entry.users++
+	sharedGroupsMu.Unlock()
+
+	// Without caching, the group holds no state once all its callers have returned: each of its calls has either
+	// completed, or been canceled and forgotten by its last caller.
+	defer func() {
+		sharedGroupsMu.Lock()
+		defer sharedGroupsMu.Unlock()
+
+		entry.users--
+		if entry.users == 0 {
+			delete(sharedGroups, groupKey)
+		}
+	}()
+
+	//nolint:forcetypeassert // The key includes the type parameters of the group.
+	return entry.group.(*SharedGroup[ResourceT, ValueT]).Submit(ctx, key, tsk)
+}
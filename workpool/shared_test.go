@@ -0,0 +1,255 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+)
+
+// sharedTask counts its executions and blocks until released or canceled.
+type sharedTask struct {
+	executions atomic.Int32
+	started    chan struct{}
+	release    chan struct{}
+	canceled   chan error
+}
+
+func newSharedTask() *sharedTask {
+	return &sharedTask{
+		started:  make(chan struct{}, 1),
+		release:  make(chan struct{}),
+		canceled: make(chan error, 1),
+	}
+}
+
+func (t *sharedTask) Execute(ctx context.Context, _ any) (int32, error) {
+	n := t.executions.Add(1)
+	t.started <- struct{}{}
+	select {
+	case <-ctx.Done():
+		t.canceled <- context.Cause(ctx)
+
+		return 0, context.Cause(ctx)
+	case <-t.release:
+		return n, nil
+	}
+}
+
+func TestSubmitSharedDeduplicates(t *testing.T) {
+	p := New[any](nil, 2)
+	p.Start()
+	defer p.Close()
+
+	tsk := newSharedTask()
+	const callers = 5
+	wg := &sync.WaitGroup{}
+	results := make(chan int32, callers)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		val, err := SubmitShared[any, int32](context.Background(), p, "key", tsk)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		results <- val
+	}()
+	<-tsk.started
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := SubmitShared[any, int32](context.Background(), p, "key", tsk)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			results <- val
+		}()
+	}
+	for !sharedRefs(p, "key", callers) {
+		// Wait for all the callers to join the shared call.
+	}
+	close(tsk.release)
+	wg.Wait()
+	close(results)
+
+	for val := range results {
+		if val != 1 {
+			t.Errorf("Expected shared result 1, got %d", val)
+		}
+	}
+	if n := tsk.executions.Load(); n != 1 {
+		t.Errorf("Expected 1 execution, got %d", n)
+	}
+}
+
+// sharedRefs returns true if the shared call for the key of the default group has the expected number of callers.
+func sharedRefs(p any, key string, refs int) bool {
+	sharedGroupsMu.Lock()
+	entry, ok := sharedGroups[sharedGroupKey{pool: p, value: (*int32)(nil)}]
+	sharedGroupsMu.Unlock()
+	if !ok {
+		return false
+	}
+	//nolint:forcetypeassert
+	g := entry.group.(*SharedGroup[any, int32])
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	call, ok := g.calls[key]
+
+	return ok && call.refs == refs
+}
+
+func TestSharedGroupReferenceCountedCancel(t *testing.T) {
+	p := New[any](nil, 1)
+	p.Start()
+	defer p.Close()
+
+	g := NewSharedGroup[any, int32](p, SharedConfig{})
+	tsk := newSharedTask()
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := g.Submit(ctx1, "key", tsk)
+		errs <- err
+	}()
+	<-tsk.started
+	go func() {
+		_, err := g.Submit(ctx2, "key", tsk)
+		errs <- err
+	}()
+	for {
+		g.mu.Lock()
+		refs := g.calls["key"].refs
+		g.mu.Unlock()
+		if refs == 2 {
+			break
+		}
+	}
+
+	// The first caller gives up, the task keeps running for the second caller.
+	cancel1()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	select {
+	case err := <-tsk.canceled:
+		t.Fatalf("Expected task not to be canceled, got %v", err)
+	default:
+	}
+
+	// The last caller gives up, the task is canceled.
+	cancel2()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := <-tsk.canceled; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected task to be canceled, got %v", err)
+	}
+}
+
+func TestSharedGroupCache(t *testing.T) {
+	p := New[any](nil, 1)
+	p.Start()
+	defer p.Close()
+
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	g := NewSharedGroup[any, int32](p, SharedConfig{TTL: time.Minute, Clock: fake})
+	tsk := newSharedTask()
+	close(tsk.release)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		val, err := g.Submit(ctx, "key", tsk)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if val != 1 {
+			t.Errorf("Expected cached result 1, got %d", val)
+		}
+	}
+	<-tsk.started
+
+	// The cached result expires.
+	fake.Advance(time.Minute)
+	if val, err := g.Submit(ctx, "key", tsk); err != nil || val != 2 {
+		t.Errorf("Expected fresh result 2, got %d (%v)", val, err)
+	}
+	<-tsk.started
+
+	g.Forget("key")
+	if val, err := g.Submit(ctx, "key", tsk); err != nil || val != 3 {
+		t.Errorf("Expected fresh result 3, got %d (%v)", val, err)
+	}
+	<-tsk.started
+}
+
+func TestSubmitSharedContextValues(t *testing.T) {
+	p := New[any](nil, 1)
+	p.Start()
+	defer p.Close()
+
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	defer cancel()
+	val, err := SubmitShared[any, string](ctx, p, "key", &funcTask{func(ctx context.Context) (string, error) {
+		//nolint:forcetypeassert
+		return ctx.Value(key{}).(string), nil
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val != "value" {
+		t.Errorf("Expected %q, got %q", "value", val)
+	}
+}
+
+type funcTask struct {
+	f func(context.Context) (string, error)
+}
+
+func (t *funcTask) Execute(ctx context.Context, _ any) (string, error) {
+	return t.f(ctx)
+}
+
+// uncomparablePool is a [types.WorkerPool] which cannot be used as a map key.
+type uncomparablePool struct {
+	types.WorkerPool[any]
+	tags []string
+}
+
+func TestSubmitSharedForgetsGroups(t *testing.T) {
+	p := New[any](nil, 1)
+	p.Start()
+	defer p.Close()
+
+	for _, pool := range []types.WorkerPool[any]{p, uncomparablePool{WorkerPool: p}} {
+		val, err := SubmitShared[any, string](context.Background(), pool, "key", &funcTask{
+			func(context.Context) (string, error) { return "value", nil },
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if val != "value" {
+			t.Errorf("Expected %q, got %q", "value", val)
+		}
+	}
+
+	sharedGroupsMu.Lock()
+	defer sharedGroupsMu.Unlock()
+	if len(sharedGroups) != 0 {
+		t.Errorf("Expected the shared groups to be forgotten, got %d", len(sharedGroups))
+	}
+}
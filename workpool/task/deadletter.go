@@ -0,0 +1,67 @@
+package task
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/internal/safeconcurrencycontext"
+)
+
+// Awaited marks the [types.ValuelessTask] created by one of the wrappers of this package as awaited: its caller
+// receives its result and handles its error, so the task is not routed to the [types.DeadLetterSink] of the pool when
+// it fails.
+// Other tasks are returned unchanged.
+//
+// The helpers submitting a task and waiting for its result, such as
+// [github.com/Izzette/go-safeconcurrency/workpool.Submit], mark the task as awaited.
+func Awaited[ResourceT any](task types.ValuelessTask[ResourceT]) types.ValuelessTask[ResourceT] {
+	if awaitable, ok := task.(awaitableTask[ResourceT]); ok {
+		return awaitable.markAwaited()
+	}
+
+	return task
+}
+
+// awaitableTask is implemented by the wrappers of this package which can be marked as awaited, see [Awaited].
+type awaitableTask[ResourceT any] interface {
+	markAwaited() types.ValuelessTask[ResourceT]
+}
+
+// deadLetterOf describes the failure of a task executed with the [context.Context].
+// The redrive function wraps the task again with the provided context, which is never canceled.
+// The boolean is false if the task succeeded, or if its context was canceled.
+func deadLetterOf[ResourceT any](
+	ctx context.Context,
+	err error,
+	redrive func(context.Context) types.ValuelessTask[ResourceT],
+) (types.DeadLetter[ResourceT], bool) {
+	if err == nil || context.Cause(ctx) != nil {
+		return types.DeadLetter[ResourceT]{}, false
+	}
+
+	attempts := uint(1)
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		attempts = retryErr.Attempts
+	}
+
+	return types.DeadLetter[ResourceT]{
+		Task:     redrive(safeconcurrencycontext.WithoutCancel(ctx)),
+		Err:      err,
+		Attempts: attempts,
+		Metadata: MetadataFromContext(ctx),
+	}, true
+}
+
+// discardEmitter implements [types.Emitter] discarding the emitted values.
+type discardEmitter[T any] struct{}
+
+// Emit implements [types.Emitter.Emit].
+func (discardEmitter[T]) Emit(ctx context.Context, _ T) error {
+	//nolint:wrapcheck
+	return context.Cause(ctx)
+}
+
+// Close implements [types.Emitter.Close].
+func (discardEmitter[T]) Close() {}
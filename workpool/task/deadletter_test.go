@@ -0,0 +1,206 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+)
+
+// flakyTask fails until it has been executed succeedAfter times.
+type flakyTask struct {
+	runs         uint
+	succeedAfter uint
+	err          error
+}
+
+func (t *flakyTask) Execute(ctx context.Context, res interface{}) (uint, error) {
+	t.runs++
+	if t.runs < t.succeedAfter {
+		return 0, t.err
+	}
+
+	return t.runs, nil
+}
+
+func TestMetadata(t *testing.T) {
+	if metadata := MetadataFromContext(context.Background()); metadata != nil {
+		t.Errorf("Expected no metadata, got %v", metadata)
+	}
+
+	parent := WithMetadata(context.Background(), "job", "email")
+	ctx := WithMetadata(parent, "id", "42")
+	if metadata := MetadataFromContext(ctx); len(metadata) != 2 || metadata["job"] != "email" || metadata["id"] != "42" {
+		t.Errorf("Expected both keys, got %v", metadata)
+	}
+	if metadata := MetadataFromContext(parent); len(metadata) != 1 {
+		t.Errorf("Expected the parent metadata to be unchanged, got %v", metadata)
+	}
+
+	// The returned map is a copy.
+	MetadataFromContext(ctx)["id"] = "43"
+	if metadata := MetadataFromContext(ctx); metadata["id"] != "42" {
+		t.Errorf("Expected metadata to be immutable, got %v", metadata)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	taskErr := errors.New("flaky")
+	inner := &flakyTask{succeedAfter: 3, err: taskErr}
+	val, err := Retry[interface{}, uint](inner, RetryConfig{MaxAttempts: 3}).Execute(context.Background(), nil)
+	if err != nil || val != 3 {
+		t.Errorf("Expected success on attempt 3, got %d (%v)", val, err)
+	}
+
+	inner = &flakyTask{succeedAfter: 10, err: taskErr}
+	_, err = Retry[interface{}, uint](inner, RetryConfig{MaxAttempts: 3}).Execute(context.Background(), nil)
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 || !errors.Is(err, taskErr) {
+		t.Errorf("Expected RetryError after 3 attempts wrapping %v, got %v", taskErr, err)
+	}
+
+	inner = &flakyTask{succeedAfter: 10, err: taskErr}
+	retryable := func(error) bool { return false }
+	_, err = Retry[interface{}, uint](inner, RetryConfig{MaxAttempts: 3, Retryable: retryable}).
+		Execute(context.Background(), nil)
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 1 {
+		t.Errorf("Expected a single attempt for non-retryable errors, got %v", err)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	inner := &flakyTask{succeedAfter: 2, err: errors.New("flaky")}
+	config := RetryConfig{
+		MaxAttempts: 2,
+		Backoff:     func(attempt uint) time.Duration { return time.Duration(attempt) * time.Second },
+		Clock:       fake,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Retry[interface{}, uint](inner, config).Execute(context.Background(), nil)
+		done <- err
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Errorf("Expected success after backoff, got %v", err)
+	}
+
+	// The backoff is interrupted by the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	inner = &flakyTask{succeedAfter: 10, err: errors.New("flaky")}
+	go func() {
+		_, err := Retry[interface{}, uint](inner, config).Execute(ctx, nil)
+		done <- err
+	}()
+	fake.WaitForTimers(1)
+	cancel()
+	var retryErr *RetryError
+	if err := <-done; !errors.As(err, &retryErr) || retryErr.Attempts != 1 {
+		t.Errorf("Expected RetryError after 1 attempt, got %v", err)
+	}
+}
+
+func TestWrapDeadLetter(t *testing.T) {
+	taskErr := errors.New("task error")
+	ctx := WithMetadata(context.Background(), "id", "42")
+	inner := &flakyTask{succeedAfter: 10, err: taskErr}
+	bareTask, res := Wrap[interface{}, uint](ctx, Retry[interface{}, uint](inner, RetryConfig{MaxAttempts: 2}))
+	bareTask.Execute(nil)
+	if err := res.Drain(); !errors.Is(err, taskErr) {
+		t.Fatalf("Expected %v, got %v", taskErr, err)
+	}
+
+	letter, failed := bareTask.(types.DeadLetterTask[interface{}]).DeadLetter()
+	if !failed {
+		t.Fatal("Expected the task to be dead-lettered")
+	}
+	if letter.Attempts != 2 || !errors.Is(letter.Err, taskErr) || letter.Metadata["id"] != "42" {
+		t.Errorf("Unexpected dead letter %+v", letter)
+	}
+
+	// The re-driven task executes the original task again.
+	letter.Task.Execute(nil)
+	if inner.runs != 4 {
+		t.Errorf("Expected 4 runs, got %d", inner.runs)
+	}
+}
+
+func TestWrapDeadLetterSucceededOrCanceled(t *testing.T) {
+	bareTask, res := Wrap[interface{}, int](context.Background(), &mockTask{val: 42})
+	bareTask.Execute(nil)
+	_ = res.Drain()
+	if _, failed := bareTask.(types.DeadLetterTask[interface{}]).DeadLetter(); failed {
+		t.Error("Expected successful task not to be dead-lettered")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bareTask, res = Wrap[interface{}, int](ctx, &mockTask{err: context.Canceled})
+	bareTask.Execute(nil)
+	_ = res.Drain()
+	if _, failed := bareTask.(types.DeadLetterTask[interface{}]).DeadLetter(); failed {
+		t.Error("Expected canceled task not to be dead-lettered")
+	}
+}
+
+func TestAwaitedDeadLetter(t *testing.T) {
+	bareTask, res := Wrap[interface{}, int](context.Background(), &mockTask{err: errors.New("task error")})
+	awaited := Awaited(bareTask)
+	awaited.Execute(nil)
+	if err := res.Drain(); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, failed := awaited.(types.DeadLetterTask[interface{}]).DeadLetter(); failed {
+		t.Error("Expected awaited task not to be dead-lettered")
+	}
+	if _, ok := awaited.(types.RejectableTask); !ok {
+		t.Error("Expected awaited task to be rejectable")
+	}
+
+	streamingTask, _ := WrapStreaming[interface{}, string](context.Background(), &failingStreamingTask{}, 1)
+	awaited = Awaited(streamingTask)
+	awaited.Execute(nil)
+	if _, failed := awaited.(types.DeadLetterTask[interface{}]).DeadLetter(); failed {
+		t.Error("Expected awaited streaming task not to be dead-lettered")
+	}
+}
+
+type failingStreamingTask struct{ runs int }
+
+func (t *failingStreamingTask) Execute(ctx context.Context, _ interface{}, h types.Emitter[string]) error {
+	t.runs++
+	if err := h.Emit(ctx, "value"); err != nil {
+		return err
+	}
+
+	return errors.New("stream error")
+}
+
+func TestWrapStreamingDeadLetter(t *testing.T) {
+	inner := &failingStreamingTask{}
+	bareTask, res := WrapStreaming[interface{}, string](context.Background(), inner, 1)
+	bareTask.Execute(nil)
+	if err := res.Drain(); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	letter, failed := bareTask.(types.DeadLetterTask[interface{}]).DeadLetter()
+	if !failed || letter.Attempts != 1 {
+		t.Fatalf("Expected a dead letter after 1 attempt, got %+v", letter)
+	}
+
+	// The re-driven task does not block on the values it emits.
+	letter.Task.Execute(nil)
+	if inner.runs != 2 {
+		t.Errorf("Expected 2 runs, got %d", inner.runs)
+	}
+	if _, failed := letter.Task.(types.DeadLetterTask[interface{}]).DeadLetter(); !failed {
+		t.Error("Expected the re-driven task to be dead-lettered again")
+	}
+}
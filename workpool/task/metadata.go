@@ -0,0 +1,37 @@
+package task
+
+import "context"
+
+// metadataKey is the [context.Context] key used to store the metadata of a task.
+type metadataKey struct{}
+
+// WithMetadata returns a copy of the [context.Context] carrying the metadata key and value, in addition to the
+// metadata already attached to the context.
+// The metadata of a task is reported in its [types.DeadLetter], for example to identify the task when it is recorded
+// by a [types.DeadLetterSink].
+func WithMetadata(ctx context.Context, key, value string) context.Context {
+	parent := MetadataFromContext(ctx)
+	metadata := make(map[string]string, len(parent)+1)
+	for k, v := range parent {
+		metadata[k] = v
+	}
+	metadata[key] = value
+
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// MetadataFromContext returns a copy of the metadata attached to the [context.Context] by [WithMetadata].
+// It returns nil if no metadata is attached.
+func MetadataFromContext(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	if metadata == nil {
+		return nil
+	}
+
+	clone := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		clone[k] = v
+	}
+
+	return clone
+}
@@ -0,0 +1,91 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+	"github.com/Izzette/go-safeconcurrency/clock"
+)
+
+// RetryConfig configures [Retry].
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Values lower than 1 are treated as 1.
+	MaxAttempts uint
+
+	// Backoff returns the delay before the attempt following the provided failed attempt (starting at 1).
+	// Attempts are retried immediately if nil.
+	Backoff func(attempt uint) time.Duration
+
+	// Retryable returns true if the error may be retried, all errors are retried if nil.
+	// Errors are never retried once the [context.Context] of the task is canceled.
+	Retryable func(error) bool
+
+	// Clock is used to wait for the backoff delay, the [clock.Real] clock is used if nil.
+	Clock types.Clock
+}
+
+// RetryError is returned by tasks created with [Retry] when their last attempt failed.
+type RetryError struct {
+	// Attempts is the number of attempts made.
+	Attempts uint
+	// Err is the error returned by the last attempt.
+	Err error
+}
+
+// Error implements the error interface for [RetryError].
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+// Unwrap implements the error interface for [RetryError].
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry decorates the [types.Task] so that it is attempted again when it fails, up to [RetryConfig.MaxAttempts] times.
+// Once the attempts are exhausted, or the error is not retryable, a [*RetryError] is returned which reports the number
+// of attempts in the [types.DeadLetter] of the task.
+//
+// The attempts are all made by the same worker, which is kept busy during the backoff delay.
+func Retry[ResourceT any, ValueT any](
+	task types.Task[ResourceT, ValueT],
+	config RetryConfig,
+) types.Task[ResourceT, ValueT] {
+	return &retryTask[ResourceT, ValueT]{task: task, config: config, clock: clock.OrReal(config.Clock)}
+}
+
+// retryTask implements [types.Task] for [Retry].
+type retryTask[ResourceT any, ValueT any] struct {
+	task   types.Task[ResourceT, ValueT]
+	config RetryConfig
+	clock  types.Clock
+}
+
+// Execute implements [types.Task.Execute].
+func (t *retryTask[ResourceT, ValueT]) Execute(ctx context.Context, resource ResourceT) (ValueT, error) {
+	for attempt := uint(1); ; attempt++ {
+		value, err := t.task.Execute(ctx, resource)
+		if err == nil {
+			return value, nil
+		}
+		if attempt >= t.config.MaxAttempts || context.Cause(ctx) != nil ||
+			(t.config.Retryable != nil && !t.config.Retryable(err)) {
+			return value, &RetryError{Attempts: attempt, Err: err}
+		}
+
+		if t.config.Backoff == nil {
+			continue
+		}
+		timer := t.clock.NewTimer(t.config.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return value, &RetryError{Attempts: attempt, Err: err}
+		case <-timer.C():
+		}
+	}
+}
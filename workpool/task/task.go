@@ -114,6 +114,8 @@ type streamingTaskWrapper[ResourceT any, ValueT any] struct {
 	task    types.StreamingTask[ResourceT, ValueT]
 	emitter types.Emitter[ValueT]
 	err     *error
+	// awaited is set by [Awaited].
+	awaited bool
 }
 
 // Execute implements [types.StreamingTask.Execute].
@@ -123,6 +125,38 @@ func (t streamingTaskWrapper[ResourceT, ValueT]) Execute(resource ResourceT) {
 	*t.err = t.task.Execute(t.ctx, resource, t.emitter)
 }
 
+// Tenant implements [types.TenantTask.Tenant].
+func (t streamingTaskWrapper[ResourceT, ValueT]) Tenant() string {
+	return tenantOf(t.ctx, t.task)
+}
+
+// Reject implements [types.RejectableTask.Reject].
+func (t streamingTaskWrapper[ResourceT, ValueT]) Reject(err error) {
+	*t.err = err
+	t.emitter.Close()
+}
+
+// DeadLetter implements [types.DeadLetterTask.DeadLetter].
+// The re-driven task discards the values it emits.
+func (t streamingTaskWrapper[ResourceT, ValueT]) DeadLetter() (types.DeadLetter[ResourceT], bool) {
+	if t.awaited {
+		return types.DeadLetter[ResourceT]{}, false
+	}
+
+	return deadLetterOf(t.ctx, *t.err, func(ctx context.Context) types.ValuelessTask[ResourceT] {
+		var err error
+
+		return streamingTaskWrapper[ResourceT, ValueT]{ctx: ctx, task: t.task, emitter: discardEmitter[ValueT]{}, err: &err}
+	})
+}
+
+// markAwaited implements [awaitableTask.markAwaited].
+func (t streamingTaskWrapper[ResourceT, ValueT]) markAwaited() types.ValuelessTask[ResourceT] {
+	t.awaited = true
+
+	return t
+}
+
 // taskWrapper is a wrapper for a [types.Task] implementing [types.ValuelessTask].
 type taskWrapper[ResourceT any, ValueT any] struct {
 	//nolint:containedctx
@@ -130,6 +164,8 @@ type taskWrapper[ResourceT any, ValueT any] struct {
 	task types.Task[ResourceT, ValueT]
 	r    chan<- ValueT
 	err  *error
+	// awaited is set by [Awaited].
+	awaited bool
 }
 
 // Execute implements [types.ValuelessTask.Execute].
@@ -146,6 +182,40 @@ func (t taskWrapper[ResourceT, ValueT]) Execute(
 	t.r <- value
 }
 
+// Tenant implements [types.TenantTask.Tenant].
+func (t taskWrapper[ResourceT, ValueT]) Tenant() string {
+	return tenantOf(t.ctx, t.task)
+}
+
+// Reject implements [types.RejectableTask.Reject].
+// The zero value is published to the results channel, as it would be if the task had returned an error.
+func (t taskWrapper[ResourceT, ValueT]) Reject(err error) {
+	defer close(t.r)
+	var zero ValueT
+	*t.err = err
+	t.r <- zero
+}
+
+// DeadLetter implements [types.DeadLetterTask.DeadLetter].
+func (t taskWrapper[ResourceT, ValueT]) DeadLetter() (types.DeadLetter[ResourceT], bool) {
+	if t.awaited {
+		return types.DeadLetter[ResourceT]{}, false
+	}
+
+	return deadLetterOf(t.ctx, *t.err, func(ctx context.Context) types.ValuelessTask[ResourceT] {
+		valuelessTask, _ := Wrap(ctx, t.task)
+
+		return valuelessTask
+	})
+}
+
+// markAwaited implements [awaitableTask.markAwaited].
+func (t taskWrapper[ResourceT, ValueT]) markAwaited() types.ValuelessTask[ResourceT] {
+	t.awaited = true
+
+	return &t
+}
+
 // taskFunc is a function that implements [types.Task].
 type taskFuncWrapper[ResourceT any] struct {
 	f types.TaskFunc[ResourceT]
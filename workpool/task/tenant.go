@@ -0,0 +1,37 @@
+package task
+
+import (
+	"context"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+// tenantKey is the [context.Context] key used to store the tenant of a task.
+type tenantKey struct{}
+
+// WithTenant returns a copy of the [context.Context] carrying the tenant identifier.
+// Tasks wrapped with a context carrying a tenant will implement [types.TenantTask] reporting this tenant, unless the
+// wrapped task implements [types.TenantTask] itself.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant identifier attached to the [context.Context] by [WithTenant].
+// The boolean is false if no tenant is attached.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+
+	return tenant, ok
+}
+
+// tenantOf returns the tenant of the task, falling back to the tenant attached to the context.
+// The empty string is returned if neither identify a tenant.
+func tenantOf(ctx context.Context, task any) string {
+	if tenantTask, ok := task.(types.TenantTask); ok {
+		return tenantTask.Tenant()
+	}
+
+	tenant, _ := TenantFromContext(ctx)
+
+	return tenant
+}
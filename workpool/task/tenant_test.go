@@ -0,0 +1,62 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Izzette/go-safeconcurrency/api/types"
+)
+
+func TestTenantFromContext(t *testing.T) {
+	if _, ok := TenantFromContext(context.Background()); ok {
+		t.Error("Expected no tenant in background context")
+	}
+
+	ctx := WithTenant(context.Background(), "acme")
+	if tenant, ok := TenantFromContext(ctx); !ok || tenant != "acme" {
+		t.Errorf("Expected tenant %q, got %q", "acme", tenant)
+	}
+}
+
+type mockTenantTask struct{ mockTask }
+
+func (t *mockTenantTask) Tenant() string { return "task" }
+
+func TestWrapTenant(t *testing.T) {
+	ctx := WithTenant(context.Background(), "context")
+
+	bareTask, _ := Wrap[interface{}, int](ctx, &mockTask{val: 42})
+	if tenant := bareTask.(types.TenantTask).Tenant(); tenant != "context" {
+		t.Errorf("Expected tenant %q, got %q", "context", tenant)
+	}
+
+	bareTask, _ = Wrap[interface{}, int](ctx, &mockTenantTask{})
+	if tenant := bareTask.(types.TenantTask).Tenant(); tenant != "task" {
+		t.Errorf("Expected tenant %q, got %q", "task", tenant)
+	}
+
+	bareTask, _ = WrapStreaming[interface{}, string](ctx, &mockStreamingTask{t}, 1)
+	if tenant := bareTask.(types.TenantTask).Tenant(); tenant != "context" {
+		t.Errorf("Expected tenant %q, got %q", "context", tenant)
+	}
+}
+
+func TestWrapReject(t *testing.T) {
+	expectedErr := errors.New("rejected")
+
+	bareTask, taskResult := Wrap[interface{}, int](context.Background(), &mockTask{val: 42})
+	bareTask.(types.RejectableTask).Reject(expectedErr)
+	if val := <-taskResult.Results(); val != 0 {
+		t.Errorf("Expected zero value, got %v", val)
+	}
+	if err := taskResult.Drain(); !errors.Is(err, expectedErr) {
+		t.Errorf("Expected error %v, got %v", expectedErr, err)
+	}
+
+	streamingTask, streamingResult := WrapStreaming[interface{}, string](context.Background(), &mockStreamingTask{t}, 1)
+	streamingTask.(types.RejectableTask).Reject(expectedErr)
+	if err := streamingResult.Drain(); !errors.Is(err, expectedErr) {
+		t.Errorf("Expected error %v, got %v", expectedErr, err)
+	}
+}
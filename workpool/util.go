@@ -42,7 +42,9 @@ func Submit[ResourceT any, ValueT any](
 	}
 
 	// Wrap the task in a ValuelessTask to be able to submit it to the pool.
+	// Its error is returned, so it must not be routed to the dead letter sink of the pool.
 	valuelessTask, taskResults := task.Wrap[ResourceT, ValueT](ctx, tsk)
+	valuelessTask = task.Awaited(valuelessTask)
 
 	// Submit the task to the pool.
 	// If context is canceled before the task is sent it should return an error.
@@ -110,7 +112,9 @@ func SubmitStreamingBuffered[ResourceT any, ValueT any](
 	defer cancel(context.Canceled)
 
 	// Wrap the task in a ValuelessTask to be able to submit it to the pool.
+	// Its error is returned, so it must not be routed to the dead letter sink of the pool.
 	valuelessTask, taskResults := task.WrapStreaming[ResourceT](ctx, tsk, buffer)
+	valuelessTask = task.Awaited(valuelessTask)
 
 	// Submit the task to the pool.
 	// If context is canceled before the task is sent it should return an error.
@@ -204,7 +208,9 @@ func SubmitFunc[ResourceT any](
 	taskFunc types.TaskFunc[ResourceT],
 ) error {
 	// Wrap the task in a ValuelessTask to be able to submit it to the pool.
+	// Its error is returned, so it must not be routed to the dead letter sink of the pool.
 	valuelessTask, taskResults := task.WrapFunc[ResourceT](ctx, taskFunc)
+	valuelessTask = task.Awaited(valuelessTask)
 
 	// Submit the task to the pool.
 	// If context is canceled before the task is sent it should return an error.